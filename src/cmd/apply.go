@@ -0,0 +1,247 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyFlags struct {
+		file string
+	}
+)
+
+var applyCmd = &cobra.Command{
+	Use:               "apply",
+	Short:             "Create or update a Toolbx container to match a manifest",
+	RunE:              apply,
+	ValidArgsFunction: completionContainerNamesFiltered,
+}
+
+func init() {
+	flags := applyCmd.Flags()
+
+	flags.StringVarP(&applyFlags.file,
+		"file",
+		"f",
+		"",
+		"Read the manifest from FILE instead of toolbox.toml, toolbox.yaml or toolbox.yml")
+
+	applyCmd.SetHelpFunc(applyHelp)
+	rootCmd.AddCommand(applyCmd)
+}
+
+func apply(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			return errors.New("this is not a Toolbx container")
+		}
+
+		exitCode, err := utils.ForwardToHost()
+		return &exitError{exitCode, err}
+	}
+
+	if len(args) > 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "too many arguments for \"apply\"\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	file := applyFlags.file
+	if file == "" {
+		var err error
+		file, err = findManifest()
+		if err != nil {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "%s\n", err)
+			fmt.Fprintf(&builder, "Use the '--file' option to select a manifest.\n")
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+	}
+
+	m, err := readManifest(file)
+	if err != nil {
+		return err
+	}
+
+	var container string
+	var containerArg string
+
+	if len(args) != 0 {
+		container = args[0]
+		containerArg = "CONTAINER"
+	} else if m.Name != "" {
+		container = m.Name
+		containerArg = "--file"
+	}
+
+	container, image, release, err := resolveContainerAndImageNames(container,
+		containerArg,
+		m.Distro,
+		m.Image,
+		m.Release)
+
+	if err != nil {
+		return err
+	}
+
+	options, err := m.getCreateOptions(container)
+	if err != nil {
+		return fmt.Errorf("file %s is invalid: %w", file, err)
+	}
+
+	logrus.Debugf("Inspecting container %s", container)
+
+	var rollback string
+
+	if containerObj, err := podman.InspectContainer(container); err == nil {
+		if !containerObj.IsToolbx() {
+			return fmt.Errorf("%s is not a Toolbx container", container)
+		}
+
+		labels := containerObj.Labels()
+		if labels[manifestLabel] == options.manifestDigest {
+			logrus.Debugf("Container %s matches manifest %s", container, file)
+			return m.provision(container)
+		}
+
+		logrus.Debugf("Container %s has manifest digest %s, instead of %s",
+			container,
+			labels[manifestLabel],
+			options.manifestDigest)
+
+		if !applyRecreateContainer(container) {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "container %s doesn't match %s\n", container, file)
+			fmt.Fprintf(&builder, "It needs to be created again to apply the changes.\n")
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+
+		// Like 'toolbox upgrade', the old container is kept aside until
+		// the new one is in place, so that it can be put back if
+		// creating the new one fails
+		rollback = container + upgradeRollbackSuffix
+		if exists, _ := podman.ContainerExists(rollback); exists {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "container %s from an earlier upgrade already exists\n", rollback)
+			fmt.Fprintf(&builder, "Use '%s upgrade --confirm' or '--rollback' first.\n", executableBase)
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+
+		logrus.Debugf("Stopping container %s", container)
+
+		if err := podman.Stop(container, nil); err != nil {
+			logrus.Debugf("Stopping container %s failed: %s", container, err)
+			return fmt.Errorf("failed to stop container %s", container)
+		}
+
+		if err := podman.RenameContainer(container, rollback); err != nil {
+			return err
+		}
+	}
+
+	if err := createContainer(container, image, release, "", options, false); err != nil {
+		applyRestore(container, rollback)
+		return err
+	}
+
+	// The user declined to download the image
+	if exists, _ := podman.ContainerExists(container); !exists {
+		applyRestore(container, rollback)
+		return fmt.Errorf("image required to create container %s", container)
+	}
+
+	if err := m.provision(container); err != nil {
+		applyRestore(container, rollback)
+		return err
+	}
+
+	if rollback != "" {
+		if err := podman.RemoveContainer(rollback, true); err != nil {
+			return err
+		}
+	}
+
+	enterCommand := getEnterCommand(container)
+	fmt.Printf("Created container: %s\n", container)
+	fmt.Printf("Enter with: %s\n", enterCommand)
+	return nil
+}
+
+func applyHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-apply"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func applyRecreateContainer(container string) bool {
+	if rootFlags.assumeYes {
+		return true
+	}
+
+	prompt := fmt.Sprintf("Container %s needs to be created again, losing changes made inside it. Continue? [y/N]",
+		container)
+	return askForConfirmation(prompt)
+}
+
+// applyRestore puts the old container back in place of the new one, if there
+// was one.
+func applyRestore(container, rollback string) {
+	if rollback == "" {
+		return
+	}
+
+	if err := upgradeRestore(container, rollback); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+	}
+}
@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/containers/toolbox/pkg/podman"
@@ -132,6 +133,7 @@ func completionDistroNames(cmd *cobra.Command, _ []string, _ string) ([]string,
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
+	completionSetUpConfiguration()
 	supportedDistros := utils.GetSupportedDistros()
 
 	return supportedDistros, cobra.ShellCompDirectiveNoFileComp
@@ -143,6 +145,8 @@ func completionImageNames(cmd *cobra.Command, _ []string, _ string) ([]string, c
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
+	completionSetUpConfiguration()
+
 	logrus.Debug("Getting all images")
 
 	var imageNames []string
@@ -157,6 +161,19 @@ func completionImageNames(cmd *cobra.Command, _ []string, _ string) ([]string, c
 		}
 	}
 
+	if releaseFlag := cmd.Flag("release"); releaseFlag != nil && releaseFlag.Changed {
+		return imageNames, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Offer the default image, as it would be resolved with the
+	// configuration, even if it wasn't pulled yet
+	if _, image, release, err := utils.ResolveContainerAndImageNames("", "", "", ""); err == nil {
+		imageFull, err := utils.GetFullyQualifiedImageFromDistros(image, release)
+		if err == nil && !slices.Contains(imageNames, imageFull) {
+			imageNames = append(imageNames, imageFull)
+		}
+	}
+
 	return imageNames, cobra.ShellCompDirectiveNoFileComp
 }
 
@@ -189,3 +206,11 @@ func completionImageNamesFiltered(_ *cobra.Command, args []string, _ string) ([]
 
 	return imageNames, cobra.ShellCompDirectiveNoFileComp
 }
+
+// completionSetUpConfiguration reads the configuration files, because the
+// completion functions are called without running the preRun hook.
+func completionSetUpConfiguration() {
+	if err := utils.SetUpConfiguration(true); err != nil {
+		logrus.Debugf("Setting up configuration for completion failed: %s", err)
+	}
+}
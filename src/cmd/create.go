@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -31,16 +32,33 @@ import (
 	"github.com/containers/toolbox/pkg/skopeo"
 	"github.com/containers/toolbox/pkg/term"
 	"github.com/containers/toolbox/pkg/utils"
+	"github.com/containers/toolbox/pkg/version"
 	"github.com/docker/go-units"
 	"github.com/godbus/dbus/v5"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
+type createOptions struct {
+	environ         []string
+	home            string
+	idleTimeout     uint
+	initHooks       []string
+	isolatedXDGDirs bool
+	manifestDigest  string
+	volumes         []volume
+}
+
 type promptForDownloadError struct {
 	ImageSize string
 }
 
+const (
+	homeLabel          = "com.github.containers.toolbox.home"
+	toolbxVersionLabel = "com.github.containers.toolbox.version"
+	xdgDirsLabel       = "com.github.containers.toolbox.xdg-dirs"
+)
+
 const (
 	alpha    = `abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ`
 	num      = `0123456789`
@@ -49,11 +67,22 @@ const (
 
 var (
 	createFlags struct {
-		authFile  string
-		container string
-		distro    string
-		image     string
-		release   string
+		authFile        string
+		container       string
+		distro          string
+		env             []string
+		envFiles        []string
+		file            string
+		home            string
+		homeShares      []string
+		idleTimeout     uint
+		image           string
+		initHooks       []string
+		isolatedHome    bool
+		isolatedXDGDirs bool
+		mounts          []string
+		release         string
+		volumes         []string
 	}
 
 	createToolboxShMounts = []struct {
@@ -92,18 +121,74 @@ func init() {
 		"",
 		"Create a Toolbx container for a different operating system distribution than the host")
 
+	flags.StringArrayVar(&createFlags.env,
+		"env",
+		nil,
+		"Set an environment variable, as KEY=VALUE, for every command run inside the Toolbx container")
+
+	flags.StringArrayVar(&createFlags.envFiles,
+		"env-file",
+		nil,
+		"Read environment variables to set inside the Toolbx container from a file")
+
+	flags.StringVarP(&createFlags.file,
+		"file",
+		"f",
+		"",
+		"Create the Toolbx container described by a manifest in FILE")
+
+	flags.StringVar(&createFlags.home,
+		"home",
+		"",
+		"Use PATH as the home directory inside the Toolbx container, instead of the one from the host")
+
+	flags.StringArrayVar(&createFlags.homeShares,
+		"home-share",
+		nil,
+		"Bind mount PATH from the home directory of the host into the separate home directory")
+
+	flags.UintVar(&createFlags.idleTimeout,
+		"idle-timeout",
+		0,
+		"Stop the Toolbx container after it had no active sessions for MINUTES (0 to never stop)")
+
 	flags.StringVarP(&createFlags.image,
 		"image",
 		"i",
 		"",
 		"Change the name of the base image used to create the Toolbx container")
 
+	flags.StringArrayVar(&createFlags.initHooks,
+		"init-hooks",
+		nil,
+		"Run the hooks in DIR from the host while initializing the Toolbx container")
+
+	flags.BoolVar(&createFlags.isolatedHome,
+		"isolated-home",
+		false,
+		"Use a separate home directory inside the Toolbx container, instead of the one from the host")
+
+	flags.BoolVar(&createFlags.isolatedXDGDirs,
+		"isolated-xdg-dirs",
+		false,
+		"Use separate XDG directories and shell history inside the Toolbx container")
+
+	flags.StringArrayVar(&createFlags.mounts,
+		"mount",
+		nil,
+		"Attach a filesystem mount to the Toolbx container, like 'podman create --mount'")
+
 	flags.StringVarP(&createFlags.release,
 		"release",
 		"r",
 		"",
 		"Create a Toolbx container for a different operating system release than the host")
 
+	flags.StringArrayVar(&createFlags.volumes,
+		"volume",
+		nil,
+		"Bind mount a path from the host or a Podman volume into the Toolbx container")
+
 	createCmd.SetHelpFunc(createHelp)
 
 	if err := createCmd.RegisterFlagCompletionFunc("distro", completionDistroNames); err != nil {
@@ -129,6 +214,21 @@ func create(cmd *cobra.Command, args []string) error {
 		return &exitError{exitCode, err}
 	}
 
+	if cmd.Flag("file").Changed {
+		for _, option := range []string{"distro", "image", "release"} {
+			if !cmd.Flag(option).Changed {
+				continue
+			}
+
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "options --file and --%s cannot be used together\n", option)
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+	}
+
 	if cmd.Flag("distro").Changed && cmd.Flag("image").Changed {
 		var builder strings.Builder
 		fmt.Fprintf(&builder, "options --distro and --image cannot be used together\n")
@@ -147,6 +247,52 @@ func create(cmd *cobra.Command, args []string) error {
 		return errors.New(errMsg)
 	}
 
+	var m *manifest
+
+	if cmd.Flag("file").Changed {
+		var err error
+		m, err = readManifest(createFlags.file)
+		if err != nil {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "invalid argument for '--file'\n")
+			fmt.Fprintf(&builder, "%s\n", err)
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+	}
+
+	if cmd.Flag("home").Changed && createFlags.isolatedHome {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "options --home and --isolated-home cannot be used together\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if (cmd.Flag("home").Changed || createFlags.isolatedHome) && createFlags.isolatedXDGDirs {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "option --isolated-xdg-dirs cannot be used with a separate home directory\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if cmd.Flag("home-share").Changed &&
+		!cmd.Flag("home").Changed &&
+		!createFlags.isolatedHome &&
+		(m == nil || !m.IsolatedHome) {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "option --home-share needs --home or --isolated-home\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
 	if cmd.Flag("authfile").Changed {
 		if !utils.PathExists(createFlags.authFile) {
 			var builder strings.Builder
@@ -168,26 +314,85 @@ func create(cmd *cobra.Command, args []string) error {
 	} else if createFlags.container != "" {
 		container = createFlags.container
 		containerArg = "--container"
+	} else if m != nil && m.Name != "" {
+		container = m.Name
+		containerArg = "--file"
+	}
+
+	distro := createFlags.distro
+	image := createFlags.image
+	release := createFlags.release
+
+	if m != nil {
+		distro = m.Distro
+		image = m.Image
+		release = m.Release
+	}
+
+	// Loading an image from an archive leaves it in the local storage, so
+	// anything that doesn't depend on it is checked before
+	if utils.ImageReferenceIsArchive(image) {
+		if container != "" && !utils.IsContainerNameValid(container) {
+			err := createErrorInvalidContainer(containerArg)
+			return err
+		}
+
+		if _, err := getCreateOptionsFromFlags(cmd, m, container); err != nil {
+			return err
+		}
+
+		var err error
+		image, err = loadImageFromArchive(image)
+		if err != nil {
+			return err
+		}
 	}
 
 	container, image, release, err := resolveContainerAndImageNames(container,
 		containerArg,
-		createFlags.distro,
-		createFlags.image,
-		createFlags.release)
+		distro,
+		image,
+		release)
 
 	if err != nil {
 		return err
 	}
 
-	if err := createContainer(container, image, release, createFlags.authFile, true); err != nil {
+	options, err := getCreateOptionsFromFlags(cmd, m, container)
+	if err != nil {
+		return err
+	}
+
+	if m == nil {
+		if err := createContainer(container, image, release, createFlags.authFile, options, true); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	if err := createContainer(container, image, release, createFlags.authFile, options, false); err != nil {
+		return err
+	}
+
+	if exists, _ := podman.ContainerExists(container); !exists {
+		return nil
+	}
+
+	if err := m.provision(container); err != nil {
 		return err
 	}
 
+	enterCommand := getEnterCommand(container)
+	fmt.Printf("Created container: %s\n", container)
+	fmt.Printf("Enter with: %s\n", enterCommand)
 	return nil
 }
 
-func createContainer(container, image, release, authFile string, showCommandToEnter bool) error {
+func createContainer(container, image, release, authFile string,
+	options *createOptions,
+	showCommandToEnter bool) error {
+
 	if container == "" {
 		panic("container not specified")
 	}
@@ -200,6 +405,15 @@ func createContainer(container, image, release, authFile string, showCommandToEn
 		panic("release not specified")
 	}
 
+	if options == nil {
+		panic("options not specified")
+	}
+
+	toolbxMountKinds := getToolbxMountKinds(options.home)
+	if err := validateVolumes(options.volumes, toolbxMountKinds); err != nil {
+		return fmt.Errorf("invalid volume: %w", err)
+	}
+
 	enterCommand := getEnterCommand(container)
 
 	logrus.Debugf("Checking if container %s already exists", container)
@@ -214,7 +428,7 @@ func createContainer(container, image, release, authFile string, showCommandToEn
 		return errors.New(errMsg)
 	}
 
-	pulled, err := pullImage(image, release, authFile)
+	pulled, err := pullImage(image, release, authFile, "image required to create Toolbx container.", false)
 	if err != nil {
 		return err
 	}
@@ -307,8 +521,40 @@ func createContainer(container, image, release, authFile string, showCommandToEn
 	}
 
 	logrus.Debugf("%s canonicalized to %s", currentUserHomeDir, homeDirEvaled)
+
+	homeDir := currentUserHomeDir
+	var homeDirLabel []string
+
+	if options.home != "" {
+		logrus.Debugf("Creating home directory %s", options.home)
+
+		if err := os.MkdirAll(options.home, 0700); err != nil {
+			logrus.Debugf("Creating home directory %s failed: %s", options.home, err)
+			return fmt.Errorf("failed to create home directory %s", options.home)
+		}
+
+		homeDirEvaled, err = filepath.EvalSymlinks(options.home)
+		if err != nil {
+			return fmt.Errorf("failed to canonicalize %s", options.home)
+		}
+
+		logrus.Debugf("%s canonicalized to %s", options.home, homeDirEvaled)
+
+		homeDir = homeDirEvaled
+		homeDirLabelArg := homeLabel + "=" + homeDirEvaled
+		homeDirLabel = []string{"--label", homeDirLabelArg}
+	}
+
 	homeDirMountArg := homeDirEvaled + ":" + homeDirEvaled + ":rslave"
 
+	var xdgDirsLabelArgs []string
+
+	if options.home == "" && options.isolatedXDGDirs {
+		xdgDirs := getContainerDataDir(container)
+		xdgDirsLabelArg := xdgDirsLabel + "=" + xdgDirs
+		xdgDirsLabelArgs = []string{"--label", xdgDirsLabelArg}
+	}
+
 	var avahiSocketMount []string
 
 	avahiSocket, err := getServiceSocket("Avahi", "avahi-daemon.socket")
@@ -403,6 +649,38 @@ func createContainer(container, image, release, authFile string, showCommandToEn
 		slashHomeLink = []string{"--home-link"}
 	}
 
+	var versionLabel []string
+
+	if toolbxVersion := version.GetVersion(); toolbxVersion != "" {
+		versionLabelArg := toolbxVersionLabel + "=" + toolbxVersion
+		versionLabel = []string{"--label", versionLabelArg}
+	}
+
+	var environLabel []string
+
+	if len(options.environ) > 0 {
+		environBytes, err := json.Marshal(options.environ)
+		if err != nil {
+			logrus.Debugf("Marshalling environment to JSON failed: %s", err)
+			return errors.New("failed to marshal the environment to JSON")
+		}
+
+		environLabelArg := envLabel + "=" + string(environBytes)
+		environLabel = []string{"--label", environLabelArg}
+	}
+
+	createOptionsLabelArg, err := marshalCreateOptionsLabel(options)
+	if err != nil {
+		return err
+	}
+
+	var manifestLabelArgs []string
+
+	if options.manifestDigest != "" {
+		manifestLabelArg := manifestLabel + "=" + options.manifestDigest
+		manifestLabelArgs = []string{"--label", manifestLabelArg}
+	}
+
 	logLevelString := podman.LogLevel.String()
 
 	userShell, err := getCurrentUserShell()
@@ -414,12 +692,21 @@ func createContainer(container, image, release, authFile string, showCommandToEn
 		"toolbox", "--log-level", "debug",
 		"init-container",
 		"--gid", currentUser.Gid,
-		"--home", currentUserHomeDir,
+		"--home", homeDir,
 		"--shell", userShell,
 		"--uid", currentUser.Uid,
 		"--user", currentUser.Username,
 	}
 
+	if options.idleTimeout > 0 {
+		idleTimeoutString := fmt.Sprint(options.idleTimeout)
+		entryPoint = append(entryPoint, []string{"--idle-timeout", idleTimeoutString}...)
+	}
+
+	for _, initHooksDir := range getInitHooksDirsForEntryPoint(options.initHooks) {
+		entryPoint = append(entryPoint, []string{"--init-hooks", initHooksDir}...)
+	}
+
 	entryPoint = append(entryPoint, slashHomeLink...)
 	entryPoint = append(entryPoint, mediaLink...)
 	entryPoint = append(entryPoint, mntLink...)
@@ -446,6 +733,13 @@ func createContainer(container, image, release, authFile string, showCommandToEn
 		"--label", "com.github.containers.toolbox=true",
 	}...)
 
+	createArgs = append(createArgs, []string{"--label", createOptionsLabelArg}...)
+	createArgs = append(createArgs, environLabel...)
+	createArgs = append(createArgs, homeDirLabel...)
+	createArgs = append(createArgs, manifestLabelArgs...)
+	createArgs = append(createArgs, xdgDirsLabelArgs...)
+	createArgs = append(createArgs, versionLabel...)
+
 	createArgs = append(createArgs, devPtsMount...)
 
 	createArgs = append(createArgs, []string{
@@ -474,6 +768,10 @@ func createContainer(container, image, release, authFile string, showCommandToEn
 	createArgs = append(createArgs, runMediaMount...)
 	createArgs = append(createArgs, toolboxShMount...)
 
+	for _, volume := range options.volumes {
+		createArgs = append(createArgs, []string{volume.option, volume.arg}...)
+	}
+
 	createArgs = append(createArgs, []string{
 		imageFull,
 	}...)
@@ -529,6 +827,203 @@ func createHelp(cmd *cobra.Command, args []string) {
 	}
 }
 
+// getHomeShareVolume returns a volume that bind mounts homeShare, which must
+// be inside the user's home directory on the host, at the same relative path
+// inside home.
+func getHomeShareVolume(homeShare, home string) (volume, error) {
+	currentUserHomeDir := getCurrentUserHomeDir()
+
+	if !filepath.IsAbs(homeShare) {
+		homeShare = filepath.Join(currentUserHomeDir, homeShare)
+	}
+
+	homeShareEvaled, err := filepath.EvalSymlinks(homeShare)
+	if err != nil {
+		return volume{}, fmt.Errorf("path %s not found", homeShare)
+	}
+
+	homeDirEvaled, err := filepath.EvalSymlinks(currentUserHomeDir)
+	if err != nil {
+		return volume{}, fmt.Errorf("failed to canonicalize %s", currentUserHomeDir)
+	}
+
+	relativePath, err := filepath.Rel(homeDirEvaled, homeShareEvaled)
+	if err != nil || relativePath == "." || strings.HasPrefix(relativePath, "..") {
+		return volume{}, fmt.Errorf("path %s is not inside %s", homeShare, currentUserHomeDir)
+	}
+
+	destination := filepath.Join(home, relativePath)
+	volumeArg := homeShareEvaled + ":" + destination + ":rslave"
+	return parseVolume(volumeArg)
+}
+
+// getCreateOptionsFromFlags returns the options for creating the container
+// from the command line and the manifest, on top of those from the
+// configuration file.
+func getCreateOptionsFromFlags(cmd *cobra.Command, m *manifest, container string) (*createOptions, error) {
+	options, err := getCreateOptionsFromConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.Flag("home").Changed {
+		home, err := filepath.Abs(createFlags.home)
+		if err != nil {
+			logrus.Debugf("Getting the absolute path to %s failed: %s", createFlags.home, err)
+			return nil, fmt.Errorf("failed to get the absolute path to %s", createFlags.home)
+		}
+
+		options.home = home
+	} else if createFlags.isolatedHome || (m != nil && m.IsolatedHome) {
+		options.home = getIsolatedHomeDir(container)
+	}
+
+	if m != nil {
+		if err := m.applyTo(options); err != nil {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "invalid argument for '--file'\n")
+			fmt.Fprintf(&builder, "%s\n", err)
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return nil, errors.New(errMsg)
+		}
+	}
+
+	if cmd.Flag("idle-timeout").Changed {
+		options.idleTimeout = createFlags.idleTimeout
+	}
+
+	if cmd.Flag("isolated-xdg-dirs").Changed {
+		options.isolatedXDGDirs = createFlags.isolatedXDGDirs
+	}
+
+	if options.home != "" && cmd.Flag("isolated-xdg-dirs").Changed && createFlags.isolatedXDGDirs {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "option --isolated-xdg-dirs cannot be used with a separate home directory\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return nil, errors.New(errMsg)
+	}
+
+	for _, homeShare := range createFlags.homeShares {
+		volume, err := getHomeShareVolume(homeShare, options.home)
+		if err != nil {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "invalid argument for '--home-share'\n")
+			fmt.Fprintf(&builder, "%s\n", err)
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return nil, errors.New(errMsg)
+		}
+
+		options.volumes = append(options.volumes, volume)
+	}
+
+	for _, initHooksDir := range createFlags.initHooks {
+		initHooksDirEvaled, err := getInitHooksDir(initHooksDir)
+		if err != nil {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "invalid argument for '--init-hooks'\n")
+			fmt.Fprintf(&builder, "%s\n", err)
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return nil, errors.New(errMsg)
+		}
+
+		options.initHooks = append(options.initHooks, initHooksDirEvaled)
+	}
+
+	for _, envFile := range createFlags.envFiles {
+		environ, err := parseEnvFile(envFile)
+		if err != nil {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "invalid argument for '--env-file'\n")
+			fmt.Fprintf(&builder, "%s\n", err)
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return nil, errors.New(errMsg)
+		}
+
+		options.environ = append(options.environ, environ...)
+	}
+
+	environ, err := parseEnvs(createFlags.env)
+	if err != nil {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--env'\n")
+		fmt.Fprintf(&builder, "%s\n", err)
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return nil, errors.New(errMsg)
+	}
+
+	options.environ = append(options.environ, environ...)
+
+	for _, volumeArg := range createFlags.volumes {
+		volume, err := parseVolume(volumeArg)
+		if err != nil {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "invalid argument for '--volume'\n")
+			fmt.Fprintf(&builder, "%s\n", err)
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return nil, errors.New(errMsg)
+		}
+
+		options.volumes = append(options.volumes, volume)
+	}
+
+	for _, mountArg := range createFlags.mounts {
+		volume, err := parseMount(mountArg)
+		if err != nil {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "invalid argument for '--mount'\n")
+			fmt.Fprintf(&builder, "%s\n", err)
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return nil, errors.New(errMsg)
+		}
+
+		options.volumes = append(options.volumes, volume)
+	}
+
+	return options, nil
+}
+
+func getCreateOptionsFromConfig() (*createOptions, error) {
+	idleTimeout, err := utils.GetIdleTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	var volumes []volume
+
+	for _, volumeArg := range utils.GetVolumes() {
+		volume, err := parseVolume(volumeArg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid volume in configuration: %w", err)
+		}
+
+		volumes = append(volumes, volume)
+	}
+
+	options := &createOptions{
+		idleTimeout:     idleTimeout,
+		isolatedXDGDirs: utils.GetIsolatedXDGDirs(),
+		volumes:         volumes,
+	}
+
+	return options, nil
+}
+
 func getDBusSystemSocket() (string, error) {
 	logrus.Debug("Resolving path to the D-Bus system socket")
 
@@ -665,8 +1160,68 @@ func getServiceSocket(serviceName string, unitName string) (string, error) {
 	return "", fmt.Errorf("failed to find a SOCK_STREAM socket for %s", unitName)
 }
 
-func pullImage(image, release, authFile string) (bool, error) {
-	if ok := utils.ImageReferenceCanBeID(image); ok {
+// loadImageFromArchive copies the image from an archive or a directory into
+// the local storage, so that it can be used without access to a registry. It
+// returns the name of the image, or its ID if it doesn't have one.
+func loadImageFromArchive(image string) (string, error) {
+	logrus.Debugf("Loading image %s", image)
+
+	if logLevel := logrus.GetLevel(); logLevel < logrus.DebugLevel {
+		s := spinner.New(spinner.CharSets[9], 500*time.Millisecond, spinner.WithWriterFile(os.Stdout))
+		s.Prefix = fmt.Sprintf("Loading %s: ", image)
+		s.Start()
+		defer s.Stop()
+	}
+
+	// An archive can contain an image that is already present, which must
+	// not be removed if it turns out not to be a Toolbx image
+	existingIDs, err := podman.GetImageIDs()
+	if err != nil {
+		logrus.Debugf("Listing images failed: %s", err)
+		return "", errors.New("failed to list images")
+	}
+
+	id, err := podman.PullFromArchive(image)
+	if err != nil {
+		logrus.Debugf("Loading image %s failed: %s", image, err)
+
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "failed to load image %s\n", image)
+		fmt.Fprintf(&builder, "Use '%s --verbose ...' for further details.", executableBase)
+
+		errMsg := builder.String()
+		return "", errors.New(errMsg)
+	}
+
+	imageObj, err := podman.InspectImage(id)
+	if err != nil {
+		logrus.Debugf("Inspecting image %s failed: %s", id, err)
+		return "", fmt.Errorf("failed to inspect image %s", id)
+	}
+
+	if !imageObj.IsToolbx() {
+		if _, existed := existingIDs[id]; existed {
+			logrus.Debugf("Image %s was already present, and won't be removed", id)
+		} else if err := podman.RemoveImage(id, false); err != nil {
+			logrus.Debugf("Removing image %s failed: %s", id, err)
+		}
+
+		return "", fmt.Errorf("image %s is not a Toolbx image", image)
+	}
+
+	if repoTags := imageObj.RepoTags(); len(repoTags) != 0 {
+		return repoTags[0], nil
+	}
+
+	return id, nil
+}
+
+// pullImage pulls the image unless it's already present locally. If update is
+// true, then an image from a registry is pulled again, to get the latest one
+// behind its tag. The user is asked for confirmation before pulling, and
+// errMsgNoPrompt says why the image is needed if that's not possible.
+func pullImage(image, release, authFile, errMsgNoPrompt string, update bool) (bool, error) {
+	if ok := utils.ImageReferenceCanBeID(image); ok && !update {
 		logrus.Debugf("Looking up image %s", image)
 		if _, err := podman.ImageExists(image); err == nil {
 			return true, nil
@@ -696,9 +1251,11 @@ func pullImage(image, release, authFile string) (bool, error) {
 		}
 	}
 
-	logrus.Debugf("Looking up image %s", imageFull)
-	if _, err := podman.ImageExists(imageFull); err == nil {
-		return true, nil
+	if !update {
+		logrus.Debugf("Looking up image %s", imageFull)
+		if _, err := podman.ImageExists(imageFull); err == nil {
+			return true, nil
+		}
 	}
 
 	domain := utils.ImageReferenceGetDomain(imageFull)
@@ -718,7 +1275,7 @@ func pullImage(image, release, authFile string) (bool, error) {
 	if promptForDownload {
 		if !term.IsTerminal(os.Stdin) || !term.IsTerminal(os.Stdout) {
 			var builder strings.Builder
-			fmt.Fprintf(&builder, "image required to create Toolbx container.\n")
+			fmt.Fprintf(&builder, "%s\n", errMsgNoPrompt)
 			fmt.Fprintf(&builder, "Use option '--assumeyes' to download the image.\n")
 			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
 
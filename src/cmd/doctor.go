@@ -0,0 +1,545 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type doctorStatus int
+
+const (
+	doctorPass doctorStatus = iota
+	doctorWarn
+	doctorFail
+)
+
+type doctorResult struct {
+	name   string
+	status doctorStatus
+	msg    string
+	hints  []string
+
+	// Applied with --fix, and returns a message describing what was done
+	fix func() (string, error)
+}
+
+const (
+	subIDRangeSize  = 65536
+	subIDRangeStart = 100000
+)
+
+var (
+	doctorFlags struct {
+		fix bool
+	}
+)
+
+var doctorCmd = &cobra.Command{
+	Use:               "doctor",
+	Short:             "Check the host for common problems",
+	RunE:              doctor,
+	ValidArgsFunction: completionEmpty,
+}
+
+func init() {
+	flags := doctorCmd.Flags()
+
+	flags.BoolVar(&doctorFlags.fix, "fix", false, "Fix the problems that can be safely fixed")
+
+	doctorCmd.SetHelpFunc(doctorHelp)
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func (status doctorStatus) String() string {
+	switch status {
+	case doctorPass:
+		return "PASS"
+	case doctorWarn:
+		return "WARN"
+	case doctorFail:
+		return "FAIL"
+	}
+
+	panicMsg := fmt.Sprintf("unexpected doctor status %d", status)
+	panic(panicMsg)
+}
+
+func doctor(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			return errors.New("this is not a Toolbx container")
+		}
+
+		if _, err := exec.LookPath("flatpak-spawn"); err != nil {
+			logrus.Debugf("Looking up flatpak-spawn failed: %s", err)
+
+			result := doctorResult{
+				name:   "flatpak-spawn",
+				status: doctorFail,
+				msg:    "not found inside the container",
+				hints:  []string{"Install flatpak-spawn to run commands on the host"},
+			}
+
+			doctorOutput([]doctorResult{result})
+			return &exitError{1, nil}
+		}
+
+		exitCode, err := utils.ForwardToHost()
+		return &exitError{exitCode, err}
+	}
+
+	if len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "too many arguments for \"doctor\"\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	checks := []func() doctorResult{
+		doctorCheckPodman,
+		doctorCheckMigration,
+		doctorCheckSubIDRanges,
+		doctorCheckCgroups,
+		doctorCheckDBusSystemSocket,
+		doctorCheckP11Kit,
+		doctorCheckStaleRuntimeFiles,
+	}
+
+	var failed bool
+	var results []doctorResult
+
+	for _, check := range checks {
+		result := check()
+
+		if doctorFlags.fix && result.fix != nil {
+			if msg, err := result.fix(); err != nil {
+				logrus.Debugf("Fixing %s failed: %s", result.name, err)
+				result.hints = []string{fmt.Sprintf("Fixing failed: %s", err)}
+			} else {
+				result.status = doctorPass
+				result.msg = msg
+				result.hints = nil
+			}
+		}
+
+		if result.status == doctorFail {
+			failed = true
+		}
+
+		results = append(results, result)
+	}
+
+	doctorOutput(results)
+
+	if failed {
+		return &exitError{1, nil}
+	}
+
+	return nil
+}
+
+func doctorHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-doctor"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func doctorCheckCgroups() doctorResult {
+	result := doctorResult{name: "cgroups"}
+
+	if cgroupsVersion == 1 {
+		result.status = doctorWarn
+		result.msg = "version 1"
+		result.hints = []string{"Some Podman features need cgroups version 2"}
+		return result
+	}
+
+	result.msg = fmt.Sprintf("version %d", cgroupsVersion)
+	return result
+}
+
+func doctorCheckDBusSystemSocket() doctorResult {
+	result := doctorResult{name: "D-Bus system socket"}
+
+	dbusSystemSocket, err := getDBusSystemSocket()
+	if err != nil {
+		result.status = doctorFail
+		result.msg = err.Error()
+		result.hints = []string{"Containers can't be created without it"}
+		return result
+	}
+
+	result.msg = dbusSystemSocket
+	return result
+}
+
+func doctorCheckMigration() doctorResult {
+	result := doctorResult{name: "Podman migration"}
+
+	podmanVersion, err := podman.GetVersion()
+	if err != nil {
+		result.status = doctorWarn
+		result.msg = "skipped because Podman is missing"
+		return result
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		logrus.Debugf("Checking Podman migration: failed to get the user config directory: %s", err)
+		result.status = doctorWarn
+		result.msg = "failed to get the user config directory"
+		return result
+	}
+
+	stampPath := configDir + "/toolbox/podman-system-migrate"
+
+	stampBytes, err := os.ReadFile(stampPath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logrus.Debugf("Checking Podman migration: failed to read migration stamp file %s: %s",
+				stampPath,
+				err)
+		}
+
+		result.status = doctorWarn
+		result.msg = fmt.Sprintf("not done for Podman %s", podmanVersion)
+		result.hints = []string{fmt.Sprintf("Run any other command, like '%s list', to migrate", executableBase)}
+		return result
+	}
+
+	stampString := string(stampBytes)
+	podmanVersionOld := strings.TrimSpace(stampString)
+	if podmanVersionOld != podmanVersion {
+		result.status = doctorWarn
+		result.msg = fmt.Sprintf("done for Podman %s, but Podman %s is installed", podmanVersionOld, podmanVersion)
+		result.hints = []string{fmt.Sprintf("Run any other command, like '%s list', to migrate", executableBase)}
+		return result
+	}
+
+	result.msg = fmt.Sprintf("done for Podman %s", podmanVersion)
+	return result
+}
+
+func doctorCheckP11Kit() doctorResult {
+	result := doctorResult{name: "p11-kit"}
+
+	if _, err := exec.LookPath("p11-kit"); err != nil {
+		logrus.Debugf("Looking up p11-kit failed: %s", err)
+		result.status = doctorWarn
+		result.msg = "p11-kit server not found"
+		result.hints = []string{"Certificates from the host won't be available inside containers"}
+		return result
+	}
+
+	if ok, err := utils.IsP11KitClientPresent(); !ok {
+		if err != nil {
+			logrus.Debugf("Looking up the p11-kit client failed: %s", err)
+		}
+
+		result.status = doctorWarn
+		result.msg = "p11-kit client not found"
+		result.hints = []string{"Certificates from the host won't be available inside containers"}
+		return result
+	}
+
+	result.msg = "server and client found"
+	return result
+}
+
+func doctorCheckPodman() doctorResult {
+	result := doctorResult{name: "Podman"}
+
+	podmanVersion, err := podman.GetVersion()
+	if err != nil {
+		logrus.Debugf("Getting the Podman version failed: %s", err)
+		result.status = doctorFail
+		result.msg = "failed to get the Podman version"
+		result.hints = []string{"Check that Podman is installed and works"}
+		return result
+	}
+
+	if !podman.CheckVersion("2.1.0") {
+		result.status = doctorWarn
+		result.msg = fmt.Sprintf("version %s", podmanVersion)
+		result.hints = []string{"Podman 2.1.0 or newer is needed for all features to work"}
+		return result
+	}
+
+	result.msg = fmt.Sprintf("version %s", podmanVersion)
+	return result
+}
+
+func doctorCheckStaleRuntimeFiles() doctorResult {
+	result := doctorResult{name: "Runtime files"}
+
+	staleRuntimeFiles, err := getStaleRuntimeFiles()
+	if err != nil {
+		logrus.Debugf("Looking up stale runtime files failed: %s", err)
+		result.status = doctorWarn
+		result.msg = "failed to look up stale runtime files"
+		return result
+	}
+
+	if len(staleRuntimeFiles) == 0 {
+		result.msg = "none are stale"
+		return result
+	}
+
+	result.status = doctorWarn
+	result.msg = fmt.Sprintf("%d stale", len(staleRuntimeFiles))
+	result.hints = append(result.hints, staleRuntimeFiles...)
+	result.hints = append(result.hints, fmt.Sprintf("Run '%s doctor --fix' to remove them", executableBase))
+
+	result.fix = func() (string, error) {
+		for _, staleRuntimeFile := range staleRuntimeFiles {
+			logrus.Debugf("Removing stale runtime file %s", staleRuntimeFile)
+
+			if err := os.RemoveAll(staleRuntimeFile); err != nil {
+				return "", fmt.Errorf("failed to remove %s", staleRuntimeFile)
+			}
+		}
+
+		msg := fmt.Sprintf("%d stale removed", len(staleRuntimeFiles))
+		return msg, nil
+	}
+
+	return result
+}
+
+func doctorCheckSubIDRanges() doctorResult {
+	result := doctorResult{name: "Sub-ID ranges"}
+
+	if currentUser.Uid == "0" {
+		result.msg = fmt.Sprintf("not needed for user %s", currentUser.Username)
+		return result
+	}
+
+	if _, err := utils.ValidateSubIDRanges(currentUser); err != nil {
+		logrus.Debugf("Looking up sub-GID and sub-UID ranges failed: %s", err)
+		result.status = doctorFail
+		result.msg = fmt.Sprintf("missing for user %s", currentUser.Username)
+
+		start := getSubIDRangeStartFromFiles("/etc/subgid", "/etc/subuid")
+		end := start + subIDRangeSize - 1
+		usermodCommand := fmt.Sprintf("sudo usermod --add-subuids %d-%d --add-subgids %d-%d %s",
+			start,
+			end,
+			start,
+			end,
+			currentUser.Username)
+
+		result.hints = []string{"Run: " + usermodCommand}
+		return result
+	}
+
+	result.msg = fmt.Sprintf("found for user %s", currentUser.Username)
+	return result
+}
+
+func doctorOutput(results []doctorResult) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	for _, result := range results {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", result.status, result.name, result.msg)
+
+		for _, hint := range result.hints {
+			fmt.Fprintf(writer, "\t\t%s\n", hint)
+		}
+	}
+
+	writer.Flush()
+}
+
+// getStaleRuntimeFiles returns the initialization stamps and session
+// directories of entry points that are no longer running, the CDI file for
+// NVIDIA if no container is running, and the socket of 'p11-kit server' if
+// the server is gone.
+func getStaleRuntimeFiles() ([]string, error) {
+	toolbxRuntimeDirectory, err := utils.GetRuntimeDirectory(currentUser)
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := podman.GetContainers()
+	if err != nil {
+		return nil, err
+	}
+
+	entryPointPIDs := make(map[int]struct{})
+	for containers.Next() {
+		container := containers.Get()
+		if entryPointPID := container.EntryPointPID(); entryPointPID > 0 {
+			entryPointPIDs[entryPointPID] = struct{}{}
+		}
+	}
+
+	var staleRuntimeFiles []string
+
+	for _, prefix := range []string{"container-initialized-", "container-sessions-"} {
+		pattern := filepath.Join(toolbxRuntimeDirectory, prefix+"*")
+		runtimeFiles, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, runtimeFile := range runtimeFiles {
+			runtimeFileBase := filepath.Base(runtimeFile)
+			entryPointPIDString := strings.TrimPrefix(runtimeFileBase, prefix)
+
+			entryPointPID, err := strconv.Atoi(entryPointPIDString)
+			if err != nil {
+				continue
+			}
+
+			if _, ok := entryPointPIDs[entryPointPID]; ok {
+				continue
+			}
+
+			staleRuntimeFiles = append(staleRuntimeFiles, runtimeFile)
+		}
+	}
+
+	// The CDI file is written again when a container is started
+	if len(entryPointPIDs) == 0 {
+		cdiFileForNvidia, err := getCDIFileForNvidia(currentUser)
+		if err != nil {
+			return nil, err
+		}
+
+		if utils.PathExists(cdiFileForNvidia) {
+			staleRuntimeFiles = append(staleRuntimeFiles, cdiFileForNvidia)
+		}
+	}
+
+	// A new server isn't started as long as the socket exists
+	p11KitServerSocket, err := utils.GetP11KitServerSocket(currentUser)
+	if err != nil {
+		return nil, err
+	}
+
+	if isSocketDead(p11KitServerSocket) {
+		staleRuntimeFiles = append(staleRuntimeFiles, p11KitServerSocket)
+	}
+
+	return staleRuntimeFiles, nil
+}
+
+// getSubIDRangeStart returns the first ID after all the ranges listed in a
+// subgid(5) or subuid(5) file, but not lower than subIDRangeStart.
+func getSubIDRangeStart(reader io.Reader) int {
+	start := subIDRangeStart
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, ":")
+		if len(fields) != 3 {
+			continue
+		}
+
+		rangeStart, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+
+		rangeCount, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+
+		if rangeEnd := rangeStart + rangeCount; rangeEnd > start {
+			start = rangeEnd
+		}
+	}
+
+	return start
+}
+
+func getSubIDRangeStartFromFiles(paths ...string) int {
+	start := subIDRangeStart
+
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			logrus.Debugf("Opening %s failed: %s", path, err)
+			continue
+		}
+
+		if fileStart := getSubIDRangeStart(file); fileStart > start {
+			start = fileStart
+		}
+
+		file.Close()
+	}
+
+	return start
+}
+
+// isSocketDead returns true if path is a socket that nothing listens on.
+func isSocketDead(path string) bool {
+	fileInfo, err := os.Lstat(path)
+	if err != nil || fileInfo.Mode()&os.ModeSocket == 0 {
+		return false
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return errors.Is(err, syscall.ECONNREFUSED)
+	}
+
+	conn.Close()
+	return false
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSubIDRangeStart(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		expect  int
+	}{
+		{
+			name:    "empty",
+			content: "",
+			expect:  100000,
+		},
+		{
+			name:    "one range",
+			content: "alice:100000:65536\n",
+			expect:  165536,
+		},
+		{
+			name:    "several ranges, out of order",
+			content: "bob:231072:65536\nalice:165536:65536\n",
+			expect:  296608,
+		},
+		{
+			name:    "range below the default start",
+			content: "alice:1000:1000\n",
+			expect:  100000,
+		},
+		{
+			name:    "comments and malformed lines",
+			content: "# comment\n\nalice:100000\nbob:foo:65536\ncarol:100000:65536\n",
+			expect:  165536,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := strings.NewReader(tc.content)
+			start := getSubIDRangeStart(reader)
+			assert.Equal(t, tc.expect, start)
+		})
+	}
+}
+
+func TestIsSocketDead(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "socket")
+	assert.False(t, isSocketDead(path))
+
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	require.NoError(t, err)
+	assert.False(t, isSocketDead(path))
+
+	listener.SetUnlinkOnClose(false)
+	listener.Close()
+	assert.True(t, isSocketDead(path))
+
+	file := filepath.Join(dir, "file")
+	err = os.WriteFile(file, nil, 0644)
+	require.NoError(t, err)
+	assert.False(t, isSocketDead(file))
+}
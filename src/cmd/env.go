@@ -0,0 +1,236 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const envLabel = "com.github.containers.toolbox.env"
+
+var (
+	envXDGDirs = []struct {
+		variable string
+		path     string
+	}{
+		{"CARGO_HOME", "cargo"},
+		{"HISTFILE", "state/history"},
+		{"XDG_CACHE_HOME", "cache"},
+		{"XDG_CONFIG_HOME", "config"},
+		{"XDG_DATA_HOME", "data"},
+		{"XDG_STATE_HOME", "state"},
+	}
+)
+
+// getContainerEnviron returns the environment variables that were stored with
+// the container by 'toolbox create --env' and 'toolbox create --env-file'.
+func getContainerEnviron(container podman.Container) ([]string, error) {
+	labels := container.Labels()
+	envLabelValue, ok := labels[envLabel]
+	if !ok || envLabelValue == "" {
+		return nil, nil
+	}
+
+	var environ []string
+	if err := json.Unmarshal([]byte(envLabelValue), &environ); err != nil {
+		logrus.Debugf("Parsing label %s of container %s failed: %s", envLabel, container.Name(), err)
+		return nil, fmt.Errorf("failed to parse the environment of container %s", container.Name())
+	}
+
+	return environ, nil
+}
+
+// getHostConfigHomeEnviron remembers XDG_CONFIG_HOME from the host in
+// TOOLBX_HOST_XDG_CONFIG_HOME, so that commands forwarded to the host from a
+// container with a separate home directory or XDG directories can still find
+// the user's toolbox.conf.
+func getHostConfigHomeEnviron() []string {
+	configHome, ok := os.LookupEnv("XDG_CONFIG_HOME")
+	if !ok || configHome == "" {
+		return nil
+	}
+
+	environ := []string{"TOOLBX_HOST_XDG_CONFIG_HOME=" + configHome}
+	return environ
+}
+
+// getContainerHomeEnviron returns the environment variables that point the
+// user at the separate home directory or XDG directories of the container, if
+// it was created with them.
+func getContainerHomeEnviron(container podman.Container) ([]string, error) {
+	labels := container.Labels()
+
+	if home := labels[homeLabel]; home != "" {
+		logrus.Debugf("Container %s has a separate home directory %s", container.Name(), home)
+
+		currentUserHomeDir := getCurrentUserHomeDir()
+		environ := []string{"HOME=" + home, "TOOLBX_HOST_HOME=" + currentUserHomeDir}
+		environ = append(environ, getHostConfigHomeEnviron()...)
+
+		for _, variable := range utils.HomeEnvironmentVariables {
+			value, ok := os.LookupEnv(variable)
+			if !ok {
+				continue
+			}
+
+			relativePath, err := filepath.Rel(currentUserHomeDir, value)
+			if err != nil || strings.HasPrefix(relativePath, "..") {
+				continue
+			}
+
+			value = filepath.Join(home, relativePath)
+			environ = append(environ, variable+"="+value)
+		}
+
+		return environ, nil
+	}
+
+	if xdgDirs := labels[xdgDirsLabel]; xdgDirs != "" {
+		logrus.Debugf("Container %s has separate XDG directories in %s", container.Name(), xdgDirs)
+
+		currentUserHomeDir := getCurrentUserHomeDir()
+		environ := []string{"TOOLBX_HOST_HOME=" + currentUserHomeDir}
+		environ = append(environ, getHostConfigHomeEnviron()...)
+
+		for _, xdgDir := range envXDGDirs {
+			path := filepath.Join(xdgDirs, xdgDir.path)
+
+			dir := path
+			if xdgDir.variable == "HISTFILE" {
+				dir = filepath.Dir(path)
+			}
+
+			if err := os.MkdirAll(dir, 0700); err != nil {
+				logrus.Debugf("Creating directory %s failed: %s", dir, err)
+				return nil, fmt.Errorf("failed to create directory %s", dir)
+			}
+
+			environ = append(environ, xdgDir.variable+"="+path)
+		}
+
+		return environ, nil
+	}
+
+	return nil, nil
+}
+
+// parseEnv parses arguments in the form KEY=VALUE or KEY. The value of the
+// latter is taken from the current environment, and if it's unset then the
+// returned string is empty.
+func parseEnv(envArg string) (string, error) {
+	key, _, hasValue := strings.Cut(envArg, "=")
+	if key == "" {
+		return "", fmt.Errorf("environment variable %s must be in the form KEY=VALUE", envArg)
+	}
+
+	if strings.ContainsAny(key, " \t\n") {
+		return "", fmt.Errorf("environment variable name %s must not contain whitespace", key)
+	}
+
+	if hasValue {
+		return envArg, nil
+	}
+
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		logrus.Debugf("Environment variable %s is unset", key)
+		return "", nil
+	}
+
+	return key + "=" + value, nil
+}
+
+// parseEnvs parses arguments in the form KEY=VALUE or KEY, and skips those
+// whose value can't be found.
+func parseEnvs(envArgs []string) ([]string, error) {
+	var environ []string
+
+	for _, envArg := range envArgs {
+		env, err := parseEnv(envArg)
+		if err != nil {
+			return nil, err
+		}
+
+		if env == "" {
+			continue
+		}
+
+		environ = append(environ, env)
+	}
+
+	return environ, nil
+}
+
+// parseEnvFile parses a file with one KEY=VALUE or KEY per line. Empty lines
+// and lines starting with '#' are ignored.
+func parseEnvFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("file %s not found", path)
+		}
+
+		logrus.Debugf("Opening %s failed: %s", path, err)
+		return nil, fmt.Errorf("failed to open %s", path)
+	}
+
+	defer file.Close()
+
+	environ, err := parseEnvReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return environ, nil
+}
+
+func parseEnvReader(reader io.Reader) ([]string, error) {
+	var envArgs []string
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		line = strings.TrimLeft(line, " \t")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		envArgs = append(envArgs, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	environ, err := parseEnvs(envArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return environ, nil
+}
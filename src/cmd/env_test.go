@@ -0,0 +1,110 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEnv(t *testing.T) {
+	t.Setenv("TOOLBX_TEST_SET", "foo")
+
+	testCases := []struct {
+		name   string
+		arg    string
+		expect string
+		ok     bool
+	}{
+		{
+			name:   "key and value",
+			arg:    "GOFLAGS=-mod=vendor",
+			expect: "GOFLAGS=-mod=vendor",
+			ok:     true,
+		},
+		{
+			name:   "key and empty value",
+			arg:    "CC=",
+			expect: "CC=",
+			ok:     true,
+		},
+		{
+			name:   "key from the environment",
+			arg:    "TOOLBX_TEST_SET",
+			expect: "TOOLBX_TEST_SET=foo",
+			ok:     true,
+		},
+		{
+			name:   "key unset in the environment",
+			arg:    "TOOLBX_TEST_UNSET",
+			expect: "",
+			ok:     true,
+		},
+		{
+			name: "missing key",
+			arg:  "=foo",
+		},
+		{
+			name: "key with whitespace",
+			arg:  "FOO BAR=baz",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env, err := parseEnv(tc.arg)
+			if !tc.ok {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, env)
+		})
+	}
+}
+
+func TestParseEnvReader(t *testing.T) {
+	t.Setenv("TOOLBX_TEST_SET", "foo")
+
+	content := `# Build settings
+GOFLAGS=-mod=vendor
+
+  CC=clang
+TOOLBX_TEST_SET
+TOOLBX_TEST_UNSET
+https_proxy=http://proxy.example.com:3128
+`
+
+	expect := []string{
+		"GOFLAGS=-mod=vendor",
+		"CC=clang",
+		"TOOLBX_TEST_SET=foo",
+		"https_proxy=http://proxy.example.com:3128",
+	}
+
+	reader := strings.NewReader(content)
+	environ, err := parseEnvReader(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, expect, environ)
+
+	reader = strings.NewReader("=foo\n")
+	_, err = parseEnvReader(reader)
+	assert.Error(t, err)
+}
@@ -0,0 +1,261 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+)
+
+type filter struct {
+	key   string
+	value string
+
+	// Only used by the 'created' key
+	age   time.Duration
+	older bool
+}
+
+type filters []filter
+
+var (
+	filterAgeRegexp = regexp.MustCompile(`^([0-9]+)([hdw])$`)
+
+	filterAgeUnits = map[string]time.Duration{
+		"h": time.Hour,
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}
+
+	// Keys that only make sense for containers, and not images
+	filterKeysContainerOnly = []string{"image", "status"}
+
+	filterKeys = []string{"created", "distro", "image", "label", "name", "release", "status"}
+)
+
+func parseFilter(filterArg string) (filter, error) {
+	i := strings.IndexAny(filterArg, "=<>")
+	if i <= 0 || i == len(filterArg)-1 {
+		return filter{}, fmt.Errorf("filter %s must be in the form KEY=VALUE", filterArg)
+	}
+
+	key := filterArg[:i]
+	operator := filterArg[i]
+	value := filterArg[i+1:]
+
+	if !slices.Contains(filterKeys, key) {
+		return filter{}, fmt.Errorf("filter key %s is unsupported", key)
+	}
+
+	if key != "created" {
+		if operator != '=' {
+			return filter{}, fmt.Errorf("filter %s must be in the form %s=VALUE", filterArg, key)
+		}
+
+		return filter{key: key, value: value}, nil
+	}
+
+	if operator == '=' {
+		return filter{}, errors.New("filter created must be in the form created<AGE or created>AGE")
+	}
+
+	matches := filterAgeRegexp.FindStringSubmatch(value)
+	if matches == nil {
+		return filter{}, fmt.Errorf("age %s must be a number followed by 'h', 'd' or 'w'", value)
+	}
+
+	count, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return filter{}, fmt.Errorf("age %s is too big", value)
+	}
+
+	unit := filterAgeUnits[matches[2]]
+	age := time.Duration(count) * unit
+	return filter{key: key, value: value, age: age, older: operator == '>'}, nil
+}
+
+func parseFilters(filterArgs []string) (filters, error) {
+	var ret filters
+
+	for _, filterArg := range filterArgs {
+		f, err := parseFilter(filterArg)
+		if err != nil {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "invalid argument for '--filter'\n")
+			fmt.Fprintf(&builder, "%s\n", err)
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return nil, errors.New(errMsg)
+		}
+
+		ret = append(ret, f)
+	}
+
+	return ret, nil
+}
+
+// containerOnlyKey returns the first key that can't be used to select images,
+// or an empty string if there is none.
+func (fs filters) containerOnlyKey() string {
+	for _, f := range fs {
+		if slices.Contains(filterKeysContainerOnly, f.key) {
+			return f.key
+		}
+	}
+
+	return ""
+}
+
+// match returns true if, for every key, at least one of the filters with that
+// key matches.
+func (fs filters) match(matchFilter func(filter) bool) bool {
+	matched := make(map[string]bool)
+
+	for _, f := range fs {
+		if matchFilter(f) {
+			matched[f.key] = true
+		} else if _, ok := matched[f.key]; !ok {
+			matched[f.key] = false
+		}
+	}
+
+	for _, ok := range matched {
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (fs filters) matchContainer(container podman.Container) bool {
+	return fs.match(func(f filter) bool {
+		switch f.key {
+		case "created":
+			return f.matchCreated(container.CreatedTime())
+		case "distro", "release":
+			image := container.Image()
+			return f.matchImageName(image)
+		case "image":
+			image := container.Image()
+			return f.matchGlob(image)
+		case "label":
+			return f.matchLabels(container.Labels())
+		case "name":
+			names := container.Names()
+			return slices.ContainsFunc(names, f.matchGlob)
+		case "status":
+			return container.Status() == f.value
+		}
+
+		panicMsg := fmt.Sprintf("unexpected filter key %s", f.key)
+		panic(panicMsg)
+	})
+}
+
+func (fs filters) matchImage(image podman.Image) bool {
+	return fs.match(func(f filter) bool {
+		switch f.key {
+		case "created":
+			return f.matchCreated(image.CreatedTime())
+		case "distro", "release":
+			names := image.Names()
+			return slices.ContainsFunc(names, f.matchImageName)
+		case "label":
+			return f.matchLabels(image.Labels())
+		case "name":
+			names := image.Names()
+			return slices.ContainsFunc(names, f.matchGlob)
+		}
+
+		panicMsg := fmt.Sprintf("unexpected filter key %s", f.key)
+		panic(panicMsg)
+	})
+}
+
+func (f filter) matchCreated(created time.Time) bool {
+	// Older Podman versions don't always report when something was created
+	if created.IsZero() {
+		return false
+	}
+
+	age := time.Since(created)
+	if f.older {
+		return age > f.age
+	}
+
+	return age < f.age
+}
+
+func (f filter) matchGlob(name string) bool {
+	if matched, err := path.Match(f.value, name); err == nil && matched {
+		return true
+	}
+
+	return false
+}
+
+func (f filter) matchImageName(image string) bool {
+	distro, release := utils.GetDistroAndReleaseForImage(image)
+	if distro == "" {
+		return false
+	}
+
+	switch f.key {
+	case "distro":
+		return distro == f.value
+	case "release":
+		if release == f.value {
+			return true
+		}
+
+		if releaseParsed, err := utils.ParseRelease(distro, f.value); err == nil {
+			return release == releaseParsed
+		}
+
+		return false
+	}
+
+	panicMsg := fmt.Sprintf("unexpected filter key %s", f.key)
+	panic(panicMsg)
+}
+
+// matchLabels matches filters in the form label=NAME and label=NAME=VALUE
+func (f filter) matchLabels(labels map[string]string) bool {
+	name, value, hasValue := strings.Cut(f.value, "=")
+
+	labelValue, ok := labels[name]
+	if !ok {
+		return false
+	}
+
+	if hasValue {
+		return labelValue == value
+	}
+
+	return true
+}
@@ -0,0 +1,327 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilter(t *testing.T) {
+	testCases := []struct {
+		name   string
+		arg    string
+		expect filter
+		ok     bool
+	}{
+		{
+			name:   "status",
+			arg:    "status=running",
+			expect: filter{key: "status", value: "running"},
+			ok:     true,
+		},
+		{
+			name:   "label with value",
+			arg:    "label=com.example.foo=bar",
+			expect: filter{key: "label", value: "com.example.foo=bar"},
+			ok:     true,
+		},
+		{
+			name:   "created, newer than 7 days",
+			arg:    "created<7d",
+			expect: filter{key: "created", value: "7d", age: 7 * 24 * time.Hour},
+			ok:     true,
+		},
+		{
+			name:   "created, older than 2 weeks",
+			arg:    "created>2w",
+			expect: filter{key: "created", value: "2w", age: 14 * 24 * time.Hour, older: true},
+			ok:     true,
+		},
+		{
+			name:   "created, older than 36 hours",
+			arg:    "created>36h",
+			expect: filter{key: "created", value: "36h", age: 36 * time.Hour, older: true},
+			ok:     true,
+		},
+		{
+			name: "created with '='",
+			arg:  "created=7d",
+		},
+		{
+			name: "created with an invalid unit",
+			arg:  "created<7y",
+		},
+		{
+			name: "status with '<'",
+			arg:  "status<running",
+		},
+		{
+			name: "unsupported key",
+			arg:  "foo=bar",
+		},
+		{
+			name: "missing value",
+			arg:  "status=",
+		},
+		{
+			name: "missing key",
+			arg:  "=running",
+		},
+		{
+			name: "missing operator",
+			arg:  "running",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := parseFilter(tc.arg)
+			if !tc.ok {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, f)
+		})
+	}
+}
+
+func TestFiltersMatch(t *testing.T) {
+	testCases := []struct {
+		name    string
+		args    []string
+		matched map[string]bool
+		expect  bool
+	}{
+		{
+			name:   "no filters",
+			expect: true,
+		},
+		{
+			name:    "one key, matched",
+			args:    []string{"status=exited"},
+			matched: map[string]bool{"status=exited": true},
+			expect:  true,
+		},
+		{
+			name:    "one key, not matched",
+			args:    []string{"status=exited"},
+			matched: map[string]bool{},
+			expect:  false,
+		},
+		{
+			name:    "same key twice, one matched",
+			args:    []string{"status=created", "status=exited"},
+			matched: map[string]bool{"status=exited": true},
+			expect:  true,
+		},
+		{
+			name:    "different keys, both matched",
+			args:    []string{"distro=fedora", "status=exited"},
+			matched: map[string]bool{"distro=fedora": true, "status=exited": true},
+			expect:  true,
+		},
+		{
+			name:    "different keys, one matched",
+			args:    []string{"distro=fedora", "status=exited"},
+			matched: map[string]bool{"status=exited": true},
+			expect:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filters, err := parseFilters(tc.args)
+			assert.NoError(t, err)
+
+			matched := filters.match(func(f filter) bool {
+				arg := f.key + "=" + f.value
+				return tc.matched[arg]
+			})
+
+			assert.Equal(t, tc.expect, matched)
+		})
+	}
+}
+
+func TestFilterMatchImageName(t *testing.T) {
+	testCases := []struct {
+		name   string
+		arg    string
+		image  string
+		expect bool
+	}{
+		{
+			name:   "distro",
+			arg:    "distro=fedora",
+			image:  "registry.fedoraproject.org/fedora-toolbox:40",
+			expect: true,
+		},
+		{
+			name:   "distro, different",
+			arg:    "distro=rhel",
+			image:  "registry.fedoraproject.org/fedora-toolbox:40",
+			expect: false,
+		},
+		{
+			name:   "distro, unknown image",
+			arg:    "distro=fedora",
+			image:  "localhost/foo:40",
+			expect: false,
+		},
+		{
+			name:   "release",
+			arg:    "release=40",
+			image:  "registry.fedoraproject.org/fedora-toolbox:40",
+			expect: true,
+		},
+		{
+			name:   "release, with prefix",
+			arg:    "release=f40",
+			image:  "registry.fedoraproject.org/fedora-toolbox:40",
+			expect: true,
+		},
+		{
+			name:   "release, different",
+			arg:    "release=39",
+			image:  "registry.fedoraproject.org/fedora-toolbox:40",
+			expect: false,
+		},
+		{
+			name:   "release, Ubuntu",
+			arg:    "release=24.04",
+			image:  "quay.io/toolbx/ubuntu-toolbox:24.04",
+			expect: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := parseFilter(tc.arg)
+			assert.NoError(t, err)
+
+			matched := f.matchImageName(tc.image)
+			assert.Equal(t, tc.expect, matched)
+		})
+	}
+}
+
+func TestFilterMatchLabels(t *testing.T) {
+	labels := map[string]string{
+		"com.github.containers.toolbox": "true",
+		"version":                       "40",
+	}
+
+	testCases := []struct {
+		name   string
+		arg    string
+		expect bool
+	}{
+		{
+			name:   "name",
+			arg:    "label=version",
+			expect: true,
+		},
+		{
+			name:   "name and value",
+			arg:    "label=version=40",
+			expect: true,
+		},
+		{
+			name:   "name and different value",
+			arg:    "label=version=39",
+			expect: false,
+		},
+		{
+			name:   "missing name",
+			arg:    "label=foo",
+			expect: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := parseFilter(tc.arg)
+			assert.NoError(t, err)
+
+			matched := f.matchLabels(labels)
+			assert.Equal(t, tc.expect, matched)
+		})
+	}
+}
+
+func TestFilterMatchCreated(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name    string
+		arg     string
+		created time.Time
+		expect  bool
+	}{
+		{
+			name:    "newer, matched",
+			arg:     "created<7d",
+			created: now.Add(-24 * time.Hour),
+			expect:  true,
+		},
+		{
+			name:    "newer, not matched",
+			arg:     "created<7d",
+			created: now.Add(-8 * 24 * time.Hour),
+			expect:  false,
+		},
+		{
+			name:    "older, matched",
+			arg:     "created>30d",
+			created: now.Add(-31 * 24 * time.Hour),
+			expect:  true,
+		},
+		{
+			name:    "older, not matched",
+			arg:     "created>30d",
+			created: now.Add(-24 * time.Hour),
+			expect:  false,
+		},
+		{
+			name:    "older, unknown",
+			arg:     "created>30d",
+			created: time.Time{},
+			expect:  false,
+		},
+		{
+			name:    "newer, unknown",
+			arg:     "created<7d",
+			created: time.Time{},
+			expect:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := parseFilter(tc.arg)
+			assert.NoError(t, err)
+
+			matched := f.matchCreated(tc.created)
+			assert.Equal(t, tc.expect, matched)
+		})
+	}
+}
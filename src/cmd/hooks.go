@@ -0,0 +1,80 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Hooks that are run on the host around starting a Toolbx container and
+// running commands inside it
+const (
+	hookPreStart  = "pre-start"
+	hookPostStart = "post-start"
+	hookPreEnter  = "pre-enter"
+	hookPostExit  = "post-exit"
+)
+
+// getHookEnviron returns the environment variables that describe the
+// container to a hook.
+func getHookEnviron(hook string, container podman.Container) []string {
+	environ := []string{
+		"TOOLBX_CONTAINER=" + container.Name(),
+		"TOOLBX_HOOK=" + hook,
+		"TOOLBX_IMAGE=" + container.Image(),
+	}
+
+	return environ
+}
+
+// runHook runs the command set for hook in the configuration with 'sh -c', if
+// any. It can read from the standard input stream, so that it can ask the
+// user for something, but its output goes to the standard error stream,
+// because the standard output stream belongs to the command run inside the
+// container.
+func runHook(hook string, container podman.Container) error {
+	command := utils.GetHook(hook)
+	if command == "" {
+		return nil
+	}
+
+	logrus.Debugf("Running %s hook for container %s: %s", hook, container.Name(), command)
+
+	environ := getHookEnviron(hook, container)
+
+	cmd := exec.Command("/bin/sh", "-c", command)
+	cmd.Env = append(os.Environ(), environ...)
+	cmd.Stdin = os.Stdin
+
+	// Keep the standard output stream clean for the command run inside
+	// the container, like 'toolbox run cat file > copy'
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		logrus.Debugf("Running %s hook for container %s failed: %s", hook, container.Name(), err)
+		return fmt.Errorf("%s hook failed for container %s", hook, container.Name())
+	}
+
+	return nil
+}
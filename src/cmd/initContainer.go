@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/signal"
 	"os/user"
 	"path/filepath"
 	"strconv"
@@ -44,6 +45,8 @@ var (
 		gid         int
 		home        string
 		homeLink    bool
+		idleTimeout uint
+		initHooks   []string
 		mediaLink   bool
 		mntLink     bool
 		monitorHost bool
@@ -103,6 +106,16 @@ func init() {
 		false,
 		"Make /home a symbolic link to /var/home")
 
+	flags.UintVar(&initContainerFlags.idleTimeout,
+		"idle-timeout",
+		0,
+		"Stop the Toolbx container after it had no active sessions for MINUTES")
+
+	flags.StringArrayVar(&initContainerFlags.initHooks,
+		"init-hooks",
+		nil,
+		"Run the hooks in DIR while initializing the Toolbx container")
+
 	flags.BoolVar(&initContainerFlags.mediaLink,
 		"media-link",
 		false,
@@ -319,11 +332,27 @@ func initContainer(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if err := runInitHooks(initContainerFlags.initHooks, targetUser); err != nil {
+		return err
+	}
+
 	logrus.Debug("Setting up daily ticker")
 
 	tickerDaily := time.NewTicker(24 * time.Hour)
 	defer tickerDaily.Stop()
 
+	var tickerIdleCh <-chan time.Time
+	idleTimeout := time.Duration(initContainerFlags.idleTimeout) * time.Minute
+
+	if idleTimeout > 0 {
+		logrus.Debugf("Setting up idle ticker for timeout %s", idleTimeout)
+
+		tickerIdle := time.NewTicker(time.Minute)
+		defer tickerIdle.Stop()
+
+		tickerIdleCh = tickerIdle.C
+	}
+
 	logrus.Debug("Setting up watches for file system events")
 
 	var watcherForHostErrors chan error
@@ -354,6 +383,12 @@ func initContainer(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	logrus.Debug("Setting up handlers for termination signals")
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, unix.SIGINT, unix.SIGTERM)
+	defer signal.Stop(signalCh)
+
 	logrus.Debug("Finished initializing container")
 
 	pid := os.Getpid()
@@ -379,6 +414,13 @@ func initContainer(cmd *cobra.Command, args []string) error {
 
 	go runUpdateDb()
 
+	sessionsDirectory, err := utils.GetSessionsDirectory(pid, targetUser)
+	if err != nil {
+		return err
+	}
+
+	lastActive := time.Now()
+
 	for {
 		select {
 		case event := <-tickerDaily.C:
@@ -387,6 +429,18 @@ func initContainer(cmd *cobra.Command, args []string) error {
 			handleFileSystemEvent(event)
 		case err := <-watcherForHostErrors:
 			logrus.Warnf("Received an error from the file system watcher: %v", err)
+		case event := <-tickerIdleCh:
+			if handleIdleTick(event, sessionsDirectory) {
+				lastActive = event
+			} else if idle := event.Sub(lastActive); idle >= idleTimeout {
+				logrus.Debugf("No active sessions for %s", idle)
+				removeInitializedStamp(initializedStamp)
+				return nil
+			}
+		case sig := <-signalCh:
+			logrus.Debugf("Handling signal %s", sig)
+			removeInitializedStamp(initializedStamp)
+			return nil
 		}
 	}
 
@@ -933,6 +987,51 @@ func handleFileSystemEvent(event fsnotify.Event) {
 	}
 }
 
+// handleIdleTick returns true if there are active sessions in the container
+func handleIdleTick(event time.Time, sessionsDirectory string) bool {
+	eventString := event.String()
+	logrus.Debugf("Handling idle tick %s", eventString)
+
+	entries, err := os.ReadDir(sessionsDirectory)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logrus.Warnf("Failed to read sessions directory %s: %v", sessionsDirectory, err)
+			return true
+		}
+
+		return false
+	}
+
+	for _, entry := range entries {
+		session := filepath.Join(sessionsDirectory, entry.Name())
+		if isSessionActive(session) {
+			logrus.Debugf("Session %s is active", session)
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSessionActive checks if the process that registered the session still
+// holds a lock on it
+func isSessionActive(session string) bool {
+	sessionFile, err := os.Open(session)
+	if err != nil {
+		return false
+	}
+
+	defer sessionFile.Close()
+
+	fd := sessionFile.Fd()
+	fdInt := int(fd)
+	if err := unix.Flock(fdInt, unix.LOCK_EX|unix.LOCK_NB); err != nil {
+		return errors.Is(err, unix.EWOULDBLOCK)
+	}
+
+	return false
+}
+
 func ldConfig(configFileBase string, dirs []string) error {
 	logrus.Debug("Updating dynamic linker cache")
 
@@ -1119,6 +1218,16 @@ func redirectPath(containerPath, target string, folder bool) error {
 	return nil
 }
 
+func removeInitializedStamp(initializedStamp string) {
+	logrus.Debugf("Removing initialization stamp %s", initializedStamp)
+
+	if err := os.Remove(initializedStamp); err != nil {
+		logrus.Warnf("Failed to remove initialization stamp %s: %v", initializedStamp, err)
+	}
+
+	logrus.Debug("Exiting")
+}
+
 func runUpdateDb() {
 	if err := shell.Run("updatedb", nil, nil, nil); err != nil {
 		logrus.Warnf("Failed to run updatedb(8): %v", err)
@@ -0,0 +1,305 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	initHooksDirImage = "/etc/toolbox/init.d"
+
+	// Created inside the container once the hooks that are only meant to
+	// run once have all succeeded
+	initHooksOnceStamp = "/var/lib/toolbox/init-hooks-once"
+)
+
+var (
+	initHookKinds = []struct {
+		dir    string
+		once   bool
+		asUser bool
+	}{
+		{"once", true, false},
+		{"once-user", true, true},
+		{"start", false, false},
+		{"start-user", false, true},
+	}
+)
+
+// getInitHooksDir checks that dir on the host can be used with
+// 'toolbox create --init-hooks', and returns its canonical path.
+func getInitHooksDir(dir string) (string, error) {
+	dirAbs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the absolute path to %s", dir)
+	}
+
+	dirEvaled, err := filepath.EvalSymlinks(dirAbs)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("directory %s not found", dir)
+		}
+
+		logrus.Debugf("Evaluating symbolic links in %s failed: %s", dirAbs, err)
+		return "", fmt.Errorf("failed to canonicalize %s", dir)
+	}
+
+	fileInfo, err := os.Stat(dirEvaled)
+	if err != nil || !fileInfo.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", dir)
+	}
+
+	return dirEvaled, nil
+}
+
+// getInitHooksDirsForEntryPoint returns the paths inside the container to the
+// hooks from the user's configuration directory on the host, and to dirs,
+// which are on the host. The host's file system is available at /run/host.
+func getInitHooksDirsForEntryPoint(dirs []string) []string {
+	var initHooksDirs []string
+
+	if configDir, err := os.UserConfigDir(); err != nil {
+		logrus.Debugf("Getting the user config directory failed: %s", err)
+	} else {
+		if configDirEvaled, err := filepath.EvalSymlinks(configDir); err == nil {
+			configDir = configDirEvaled
+		}
+
+		configInitHooksDir := filepath.Join(configDir, "toolbox", "init.d")
+		initHooksDirs = append(initHooksDirs, configInitHooksDir)
+	}
+
+	initHooksDirs = append(initHooksDirs, dirs...)
+
+	for i, dir := range initHooksDirs {
+		initHooksDirs[i] = filepath.Join("/run/host", dir)
+	}
+
+	return initHooksDirs
+}
+
+// getInitHooks returns the executable files in the kind sub-directory of each
+// of the dirs, sorted by their names. A file in a later directory overrides
+// one with the same name in an earlier directory.
+func getInitHooks(dirs []string, kind string) ([]string, error) {
+	hooks := make(map[string]string)
+
+	for _, dir := range dirs {
+		kindDir := filepath.Join(dir, kind)
+
+		entries, err := os.ReadDir(kindDir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			logrus.Debugf("Reading directory %s failed: %s", kindDir, err)
+			return nil, fmt.Errorf("failed to read directory %s", kindDir)
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			if strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+				continue
+			}
+
+			path := filepath.Join(kindDir, name)
+
+			fileInfo, err := os.Stat(path)
+			if err != nil {
+				logrus.Debugf("Skipping init hook %s: %s", path, err)
+				continue
+			}
+
+			if !fileInfo.Mode().IsRegular() || fileInfo.Mode().Perm()&0111 == 0 {
+				logrus.Debugf("Skipping init hook %s: not an executable file", path)
+				continue
+			}
+
+			hooks[name] = path
+		}
+	}
+
+	names := make([]string, 0, len(hooks))
+	for name := range hooks {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		paths = append(paths, hooks[name])
+	}
+
+	return paths, nil
+}
+
+// runInitHooks runs the hooks from the image, the host and those given to
+// 'toolbox create'. Failing hooks are logged, and don't stop the container
+// from being initialized.
+func runInitHooks(dirs []string, targetUser *user.User) error {
+	dirs = append([]string{initHooksDirImage}, dirs...)
+	onceDone := utils.PathExists(initHooksOnceStamp)
+	onceFailed := false
+
+	for _, kind := range initHookKinds {
+		if kind.once && onceDone {
+			logrus.Debugf("Skipping init hooks in %s: already run", kind.dir)
+			continue
+		}
+
+		hooks, err := getInitHooks(dirs, kind.dir)
+		if err != nil {
+			return err
+		}
+
+		for _, hook := range hooks {
+			var runAs *user.User
+			if kind.asUser {
+				runAs = targetUser
+			}
+
+			if err := runInitHook(hook, runAs); err != nil {
+				logrus.Warnf("Init hook %s failed: %s", hook, err)
+
+				if kind.once {
+					onceFailed = true
+				}
+			}
+		}
+	}
+
+	if onceDone || onceFailed {
+		return nil
+	}
+
+	logrus.Debugf("Creating stamp %s", initHooksOnceStamp)
+
+	onceStampDir := filepath.Dir(initHooksOnceStamp)
+	if err := os.MkdirAll(onceStampDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s", onceStampDir)
+	}
+
+	if err := os.WriteFile(initHooksOnceStamp, nil, 0644); err != nil {
+		return fmt.Errorf("failed to create stamp %s", initHooksOnceStamp)
+	}
+
+	return nil
+}
+
+// runInitHook runs hook as root, or as targetUser if it's not nil, and logs
+// its output line by line.
+func runInitHook(hook string, targetUser *user.User) error {
+	name := filepath.Base(hook)
+	logrus.Infof("Running init hook %s", hook)
+
+	reader, writer := io.Pipe()
+	defer reader.Close()
+
+	cmd := exec.Command(hook)
+	cmd.Dir = "/"
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+
+	if targetUser != nil {
+		credential, err := getInitHookCredential(targetUser)
+		if err != nil {
+			return err
+		}
+
+		cmd.Dir = targetUser.HomeDir
+		cmd.Env = append(os.Environ(),
+			"HOME="+targetUser.HomeDir,
+			"LOGNAME="+targetUser.Username,
+			"USER="+targetUser.Username)
+		cmd.SysProcAttr = &syscall.SysProcAttr{Credential: credential}
+	}
+
+	if err := cmd.Start(); err != nil {
+		writer.Close()
+		return err
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			line := scanner.Text()
+			logrus.Infof("%s: %s", name, line)
+		}
+
+		// Keep the hook from blocking on overly long lines
+		if _, err := io.Copy(io.Discard, reader); err != nil {
+			logrus.Debugf("Reading output of init hook %s failed: %s", name, err)
+		}
+	}()
+
+	err := cmd.Wait()
+	writer.Close()
+	<-done
+	return err
+}
+
+func getInitHookCredential(targetUser *user.User) (*syscall.Credential, error) {
+	uid, err := strconv.ParseUint(targetUser.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse user ID %s", targetUser.Uid)
+	}
+
+	gid, err := strconv.ParseUint(targetUser.Gid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse group ID %s", targetUser.Gid)
+	}
+
+	groupIDs, err := targetUser.GroupIds()
+	if err != nil {
+		logrus.Debugf("Getting groups of user %s failed: %s", targetUser.Username, err)
+		groupIDs = nil
+	}
+
+	var groups []uint32
+	for _, groupID := range groupIDs {
+		group, err := strconv.ParseUint(groupID, 10, 32)
+		if err != nil {
+			continue
+		}
+
+		groups = append(groups, uint32(group))
+	}
+
+	credential := &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid), Groups: groups}
+	return credential, nil
+}
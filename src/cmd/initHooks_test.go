@@ -0,0 +1,102 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetInitHooks(t *testing.T) {
+	image := t.TempDir()
+	host := t.TempDir()
+
+	files := []struct {
+		path string
+		mode os.FileMode
+	}{
+		{filepath.Join(image, "once", "10-certificates"), 0755},
+		{filepath.Join(image, "once", "20-repositories"), 0755},
+		{filepath.Join(image, "once", "README"), 0644},
+		{filepath.Join(image, "start", "10-mounts"), 0755},
+		{filepath.Join(host, "once", "05-proxy"), 0755},
+		{filepath.Join(host, "once", "20-repositories"), 0755},
+		{filepath.Join(host, "once", ".hidden"), 0755},
+		{filepath.Join(host, "once", "30-backup~"), 0755},
+	}
+
+	for _, file := range files {
+		err := os.MkdirAll(filepath.Dir(file.path), 0755)
+		assert.NoError(t, err)
+
+		err = os.WriteFile(file.path, []byte("#!/bin/sh\n"), file.mode)
+		assert.NoError(t, err)
+	}
+
+	err := os.MkdirAll(filepath.Join(host, "start-user", "sub-directory"), 0755)
+	assert.NoError(t, err)
+
+	dirs := []string{image, host, filepath.Join(t.TempDir(), "missing")}
+
+	hooks, err := getInitHooks(dirs, "once")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(host, "once", "05-proxy"),
+		filepath.Join(image, "once", "10-certificates"),
+		filepath.Join(host, "once", "20-repositories"),
+	}, hooks)
+
+	hooks, err = getInitHooks(dirs, "start")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(image, "start", "10-mounts")}, hooks)
+
+	hooks, err = getInitHooks(dirs, "start-user")
+	assert.NoError(t, err)
+	assert.Empty(t, hooks)
+
+	hooks, err = getInitHooks(dirs, "once-user")
+	assert.NoError(t, err)
+	assert.Empty(t, hooks)
+}
+
+func TestGetInitHooksDir(t *testing.T) {
+	dir := t.TempDir()
+
+	dirEvaled, err := filepath.EvalSymlinks(dir)
+	assert.NoError(t, err)
+
+	link := filepath.Join(dir, "link")
+	err = os.Symlink(dir, link)
+	assert.NoError(t, err)
+
+	initHooksDir, err := getInitHooksDir(link)
+	assert.NoError(t, err)
+	assert.Equal(t, dirEvaled, initHooksDir)
+
+	_, err = getInitHooksDir(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+
+	file := filepath.Join(dir, "file")
+	err = os.WriteFile(file, nil, 0644)
+	assert.NoError(t, err)
+
+	_, err = getInitHooksDir(file)
+	assert.Error(t, err)
+}
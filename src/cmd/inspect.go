@@ -0,0 +1,329 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type inspectMount struct {
+	Destination string `json:"destination"`
+	Kind        string `json:"kind"`
+}
+
+type inspectOutput struct {
+	Name               string         `json:"name"`
+	ID                 string         `json:"id"`
+	Image              string         `json:"image"`
+	Distro             string         `json:"distro"`
+	Release            string         `json:"release"`
+	Created            time.Time      `json:"created"`
+	Status             string         `json:"status"`
+	EntryPointPID      int            `json:"entryPointPID"`
+	Initialized        bool           `json:"initialized"`
+	CDIForNvidiaOnHost bool           `json:"cdiForNvidiaOnHost"`
+	ToolbxVersion      string         `json:"toolbxVersion"`
+	Mounts             []inspectMount `json:"mounts"`
+}
+
+var (
+	inspectFlags struct {
+		format string
+	}
+
+	// Mounts with a fixed destination that are added by 'toolbox create'
+	toolbxMountKinds = map[string]string{
+		"/dev":                      "devices",
+		"/etc/profile.d/toolbox.sh": "toolbox.sh",
+		"/media":                    "media",
+		"/mnt":                      "mnt",
+		"/run/host":                 "host",
+		"/run/media":                "run-media",
+		"/usr/bin/toolbox":          "toolbox",
+	}
+)
+
+var inspectCmd = &cobra.Command{
+	Use:               "inspect",
+	Short:             "Display Toolbx-specific information about a container",
+	RunE:              inspect,
+	ValidArgsFunction: completionContainerNamesFiltered,
+}
+
+func init() {
+	flags := inspectCmd.Flags()
+
+	flags.StringVar(&inspectFlags.format,
+		"format",
+		"text",
+		"Change the output format to 'json' or 'text'")
+
+	inspectCmd.SetHelpFunc(inspectHelp)
+	rootCmd.AddCommand(inspectCmd)
+}
+
+func inspect(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			return errors.New("this is not a Toolbx container")
+		}
+
+		exitCode, err := utils.ForwardToHost()
+		return &exitError{exitCode, err}
+	}
+
+	if inspectFlags.format != "json" && inspectFlags.format != "text" {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--format'\n")
+		fmt.Fprintf(&builder, "Supported values are 'json' and 'text'.\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if len(args) > 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "too many arguments for \"inspect\"\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	container := utils.ContainerNameDefault
+	if len(args) != 0 {
+		container = args[0]
+	}
+
+	logrus.Debugf("Inspecting container %s", container)
+
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		logrus.Debugf("Inspecting container %s failed: %s", container, err)
+		err := createErrorContainerNotFound(container)
+		return err
+	}
+
+	if !containerObj.IsToolbx() {
+		return fmt.Errorf("%s is not a Toolbx container", container)
+	}
+
+	output, err := getInspectOutput(containerObj)
+	if err != nil {
+		return err
+	}
+
+	if inspectFlags.format == "json" {
+		data, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			logrus.Debugf("Marshalling container %s to JSON failed: %s", container, err)
+			return fmt.Errorf("failed to marshal container %s to JSON", container)
+		}
+
+		fmt.Println(string(data))
+		return nil
+	}
+
+	inspectOutputText(output)
+	return nil
+}
+
+func inspectHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-inspect"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// getToolbxMountKinds returns the mounts added by 'toolbox create'. If home is
+// empty, the user's home directory from the host is used.
+func getToolbxMountKinds(home string) map[string]string {
+	mountKinds := make(map[string]string)
+	for destination, kind := range toolbxMountKinds {
+		mountKinds[destination] = kind
+	}
+
+	if dbusSystemSocket, err := getDBusSystemSocket(); err == nil {
+		mountKinds[dbusSystemSocket] = "dbus"
+	}
+
+	if home != "" {
+		mountKinds[home] = "home"
+	} else {
+		currentUserHomeDir := getCurrentUserHomeDir()
+		if homeDirEvaled, err := filepath.EvalSymlinks(currentUserHomeDir); err == nil {
+			mountKinds[homeDirEvaled] = "home"
+		}
+	}
+
+	if currentUser.Uid == "0" {
+		if runtimeDirectory, err := utils.GetRuntimeDirectory(currentUser); err == nil {
+			mountKinds[runtimeDirectory] = "runtime"
+		}
+	} else if xdgRuntimeDir := os.Getenv("XDG_RUNTIME_DIR"); xdgRuntimeDir != "" {
+		mountKinds[xdgRuntimeDir] = "runtime"
+	}
+
+	services := []struct {
+		kind     string
+		name     string
+		unitName string
+	}{
+		{"avahi", "Avahi", "avahi-daemon.socket"},
+		{"kcm", "KCM", "sssd-kcm.socket"},
+		{"pcsc", "pcsc", "pcscd.socket"},
+	}
+
+	for _, service := range services {
+		socket, err := getServiceSocket(service.name, service.unitName)
+		if err != nil {
+			logrus.Debug(err)
+			continue
+		}
+
+		mountKinds[socket] = service.kind
+	}
+
+	return mountKinds
+}
+
+func getInspectOutput(container podman.Container) (*inspectOutput, error) {
+	image := container.Image()
+	distro, release := utils.GetDistroAndReleaseForImage(image)
+
+	entryPointPID := container.EntryPointPID()
+	var initialized bool
+
+	if entryPointPID > 0 {
+		initializedStamp, err := utils.GetInitializedStamp(entryPointPID, currentUser)
+		if err != nil {
+			return nil, err
+		}
+
+		initialized = utils.PathExists(initializedStamp)
+	}
+
+	cdiFileForNvidia, err := getCDIFileForNvidia(currentUser)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := container.Labels()
+	mountKinds := getToolbxMountKinds(labels[homeLabel])
+	mounts := []inspectMount{}
+
+	for _, destination := range container.Mounts() {
+		kind, ok := mountKinds[destination]
+		if !ok {
+			kind = "other"
+		}
+
+		mounts = append(mounts, inspectMount{destination, kind})
+	}
+
+	output := &inspectOutput{
+		Name:               container.Name(),
+		ID:                 container.ID(),
+		Image:              image,
+		Distro:             distro,
+		Release:            release,
+		Created:            container.CreatedTime(),
+		Status:             container.Status(),
+		EntryPointPID:      entryPointPID,
+		Initialized:        initialized,
+		CDIForNvidiaOnHost: utils.PathExists(cdiFileForNvidia),
+		ToolbxVersion:      labels[toolbxVersionLabel],
+		Mounts:             mounts,
+	}
+
+	return output, nil
+}
+
+func inspectOutputText(output *inspectOutput) {
+	formatBool := func(value bool) string {
+		if value {
+			return "yes"
+		}
+
+		return "no"
+	}
+
+	formatString := func(value string) string {
+		if value == "" {
+			return "unknown"
+		}
+
+		return value
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(writer, "Name:\t%s\n", output.Name)
+	fmt.Fprintf(writer, "ID:\t%s\n", utils.ShortID(output.ID))
+	fmt.Fprintf(writer, "Image:\t%s\n", output.Image)
+	fmt.Fprintf(writer, "Distribution:\t%s\n", formatString(output.Distro))
+	fmt.Fprintf(writer, "Release:\t%s\n", formatString(output.Release))
+	fmt.Fprintf(writer, "Created:\t%s\n", utils.HumanDuration(output.Created.Unix()))
+	fmt.Fprintf(writer, "Status:\t%s\n", output.Status)
+
+	if output.EntryPointPID > 0 {
+		fmt.Fprintf(writer, "Entry point PID:\t%d\n", output.EntryPointPID)
+	} else {
+		fmt.Fprintf(writer, "Entry point PID:\t%s\n", "none")
+	}
+
+	fmt.Fprintf(writer, "Initialized:\t%s\n", formatBool(output.Initialized))
+	fmt.Fprintf(writer, "CDI for NVIDIA on host:\t%s\n", formatBool(output.CDIForNvidiaOnHost))
+	fmt.Fprintf(writer, "Created by Toolbx:\t%s\n", formatString(output.ToolbxVersion))
+	writer.Flush()
+
+	fmt.Println("Mounts:")
+
+	writer = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, mount := range output.Mounts {
+		fmt.Fprintf(writer, "  %s\t%s\n", mount.Destination, mount.Kind)
+	}
+
+	writer.Flush()
+}
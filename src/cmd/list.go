@@ -17,10 +17,15 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
+	"text/template"
+	"time"
 
 	"github.com/containers/toolbox/pkg/podman"
 	"github.com/containers/toolbox/pkg/term"
@@ -29,8 +34,38 @@ import (
 	"github.com/spf13/cobra"
 )
 
+type listContainer struct {
+	ID      string            `json:"id"`
+	Names   []string          `json:"names"`
+	Created *time.Time        `json:"created,omitempty"`
+	Status  string            `json:"status"`
+	Image   string            `json:"image"`
+	Labels  map[string]string `json:"labels"`
+
+	EndOfLife       bool  `json:"end-of-life"`
+	UpdateAvailable *bool `json:"update-available,omitempty"`
+}
+
+type listImage struct {
+	ID      string            `json:"id"`
+	Names   []string          `json:"names"`
+	Created *time.Time        `json:"created,omitempty"`
+	Labels  map[string]string `json:"labels"`
+
+	EndOfLife       bool  `json:"end-of-life"`
+	UpdateAvailable *bool `json:"update-available,omitempty"`
+}
+
+type listJSON struct {
+	Containers []listContainer `json:"containers"`
+	Images     []listImage     `json:"images"`
+}
+
 var (
 	listFlags struct {
+		checkUpdates   bool
+		filters        []string
+		format         string
 		onlyContainers bool
 		onlyImages     bool
 	}
@@ -46,12 +81,27 @@ var listCmd = &cobra.Command{
 func init() {
 	flags := listCmd.Flags()
 
+	flags.BoolVar(&listFlags.checkUpdates,
+		"check-updates",
+		false,
+		"Check if newer images are available in the registries")
+
 	flags.BoolVarP(&listFlags.onlyContainers,
 		"containers",
 		"c",
 		false,
 		"List only Toolbx containers, not images")
 
+	flags.StringArrayVar(&listFlags.filters,
+		"filter",
+		nil,
+		"Only list Toolbx containers and images that match the filter")
+
+	flags.StringVar(&listFlags.format,
+		"format",
+		"table",
+		"Change the output format to 'json', 'table' or a Go template")
+
 	flags.BoolVarP(&listFlags.onlyImages,
 		"images",
 		"i",
@@ -72,6 +122,23 @@ func list(cmd *cobra.Command, args []string) error {
 		return &exitError{exitCode, err}
 	}
 
+	var listTemplate *template.Template
+
+	switch listFlags.format {
+	case "json", "table":
+	default:
+		var err error
+		listTemplate, err = parseListTemplate(listFlags.format)
+		if err != nil {
+			return err
+		}
+	}
+
+	filters, err := parseFilters(listFlags.filters)
+	if err != nil {
+		return err
+	}
+
 	lsContainers := true
 	lsImages := true
 
@@ -81,9 +148,21 @@ func list(cmd *cobra.Command, args []string) error {
 		lsImages = false
 	}
 
+	if key := filters.containerOnlyKey(); key != "" {
+		if !lsContainers {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "filter %s cannot be used with images\n", key)
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+
+		lsImages = false
+	}
+
 	var images *podman.Images
 	var containers *podman.Containers
-	var err error
 
 	if lsImages {
 		logrus.Debug("Getting all images")
@@ -93,6 +172,8 @@ func list(cmd *cobra.Command, args []string) error {
 			logrus.Debugf("Getting all images failed: %s", err)
 			return errors.New("failed to get images")
 		}
+
+		images = images.Filter(filters.matchImage)
 	}
 
 	if lsContainers {
@@ -103,9 +184,28 @@ func list(cmd *cobra.Command, args []string) error {
 			logrus.Debugf("Getting all containers failed: %s", err)
 			return errors.New("failed to get containers")
 		}
+
+		containers = containers.Filter(filters.matchContainer)
+	}
+
+	var updates map[string]bool
+	if listFlags.checkUpdates {
+		updates = getListUpdates(images, containers)
+	}
+
+	switch listFlags.format {
+	case "json":
+		if err := listOutputJSON(images, containers, updates); err != nil {
+			return err
+		}
+	case "table":
+		listOutput(images, containers, updates)
+	default:
+		if err := listOutputTemplate(listTemplate, images, containers, updates); err != nil {
+			return err
+		}
 	}
 
-	listOutput(images, containers)
 	return nil
 }
 
@@ -130,20 +230,163 @@ func listHelp(cmd *cobra.Command, args []string) {
 	}
 }
 
-func listOutput(images *podman.Images, containers *podman.Containers) {
+func getListContainers(containers *podman.Containers, updates map[string]bool) []listContainer {
+	ret := make([]listContainer, 0, containers.Len())
+
+	for containers.Next() {
+		container := containers.Get()
+
+		labels := container.Labels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+
+		ret = append(ret, listContainer{
+			ID:      container.ID(),
+			Names:   container.Names(),
+			Created: getListCreated(container.CreatedTime()),
+			Status:  container.Status(),
+			Image:   container.Image(),
+			Labels:  labels,
+
+			EndOfLife:       isImageEndOfLife(container.Image()),
+			UpdateAvailable: getListUpdateAvailable(updates, container.ImageID()),
+		})
+	}
+
+	containers.Reset()
+	return ret
+}
+
+// getListImages lists every image only once, with all of its names.
+func getListImages(images *podman.Images, updates map[string]bool) []listImage {
+	mergedImages := getMergedImages(images)
+	ret := make([]listImage, 0, len(mergedImages))
+
+	for _, mergedImage := range mergedImages {
+		image := mergedImage.image
+		names := mergedImage.names
+
+		labels := image.Labels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+
+		ret = append(ret, listImage{
+			ID:      image.ID(),
+			Names:   names,
+			Created: getListCreated(image.CreatedTime()),
+			Labels:  labels,
+
+			EndOfLife:       len(names) != 0 && isImageEndOfLife(names[0]),
+			UpdateAvailable: getListUpdateAvailable(updates, image.ID()),
+		})
+	}
+
+	return ret
+}
+
+// getListCreated returns when a container or image was created, or nil if
+// it's unknown because an older Podman version didn't report it.
+func getListCreated(created time.Time) *time.Time {
+	if created.IsZero() {
+		return nil
+	}
+
+	return &created
+}
+
+// getListUpdateAvailable returns whether a newer image is available for the
+// image with the given ID, or nil if updates weren't or couldn't be checked.
+func getListUpdateAvailable(updates map[string]bool, imageID string) *bool {
+	updateAvailable, ok := updates[imageID]
+	if !ok {
+		return nil
+	}
+
+	return &updateAvailable
+}
+
+// getListUpdates checks the images, and those of the containers, for updates
+// and returns a map from their IDs to whether a newer image is available.
+func getListUpdates(images *podman.Images, containers *podman.Containers) map[string]bool {
+	imagesToCheck := make(map[string]string)
+
+	for images.Next() {
+		image := images.Get()
+		if name := image.Name(); name != "<none>" {
+			imagesToCheck[image.ID()] = name
+		}
+	}
+
+	images.Reset()
+
+	for containers.Next() {
+		container := containers.Get()
+		if id := container.ImageID(); id != "" {
+			imagesToCheck[id] = container.Image()
+		}
+	}
+
+	containers.Reset()
+
+	logrus.Debug("Checking images for updates")
+
+	updates, failed := checkImageUpdates(context.Background(), imagesToCheck)
+	for _, image := range failed {
+		fmt.Fprintf(os.Stderr, "Warning: failed to check image %s for updates\n", image)
+	}
+
+	return updates
+}
+
+// getListImageColumn returns the text in the IMAGE NAME column of the table,
+// which marks images for releases that reached their end of life.
+func getListImageColumn(image string) string {
+	if isImageEndOfLife(image) {
+		return image + " (end-of-life)"
+	}
+
+	return image
+}
+
+// getListUpdateColumn returns the text in the UPDATE column of the table for
+// the image with the given ID.
+func getListUpdateColumn(updates map[string]bool, imageID string) string {
+	updateAvailable, ok := updates[imageID]
+	if !ok {
+		return "unknown"
+	} else if updateAvailable {
+		return "available"
+	}
+
+	return "none"
+}
+
+func listOutput(images *podman.Images, containers *podman.Containers, updates map[string]bool) {
 	if images.Len() != 0 {
 		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintf(writer, "%s\t%s\t%s\n", "IMAGE ID", "IMAGE NAME", "CREATED")
+		fmt.Fprintf(writer, "%s\t%s\t%s", "IMAGE ID", "IMAGE NAME", "CREATED")
+		if updates != nil {
+			fmt.Fprintf(writer, "\t%s", "UPDATE")
+		}
+
+		fmt.Fprintf(writer, "\n")
 
 		for images.Next() {
 			image := images.Get()
 			created := image.Created()
-			name := image.Name()
+			name := getListImageColumn(image.Name())
 
 			id := image.ID()
 			shortID := utils.ShortID(id)
 
-			fmt.Fprintf(writer, "%s\t%s\t%s\n", shortID, name, created)
+			fmt.Fprintf(writer, "%s\t%s\t%s", shortID, name, created)
+			if updates != nil {
+				fmt.Fprintf(writer, "\t%s", getListUpdateColumn(updates, id))
+			}
+
+			fmt.Fprintf(writer, "\n")
 		}
 
 		writer.Flush()
@@ -172,6 +415,10 @@ func listOutput(images *podman.Images, containers *podman.Containers) {
 			"STATUS",
 			"IMAGE NAME")
 
+		if updates != nil {
+			fmt.Fprintf(writer, "\t%s", "UPDATE")
+		}
+
 		if term.IsTerminal(os.Stdout) {
 			fmt.Fprintf(writer, "%s", resetColor)
 		}
@@ -199,7 +446,7 @@ func listOutput(images *podman.Images, containers *podman.Containers) {
 			}
 
 			created := container.Created()
-			image := container.Image()
+			image := getListImageColumn(container.Image())
 			name := container.Name()
 
 			id := container.ID()
@@ -208,6 +455,9 @@ func listOutput(images *podman.Images, containers *podman.Containers) {
 			status := container.Status()
 
 			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s", shortID, name, created, status, image)
+			if updates != nil {
+				fmt.Fprintf(writer, "\t%s", getListUpdateColumn(updates, container.ImageID()))
+			}
 
 			if term.IsTerminal(os.Stdout) {
 				fmt.Fprintf(writer, "%s", resetColor)
@@ -219,3 +469,75 @@ func listOutput(images *podman.Images, containers *podman.Containers) {
 		writer.Flush()
 	}
 }
+
+func listOutputJSON(images *podman.Images, containers *podman.Containers, updates map[string]bool) error {
+	output := listJSON{
+		Containers: getListContainers(containers, updates),
+		Images:     getListImages(images, updates),
+	}
+
+	data, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		logrus.Debugf("Marshalling list to JSON failed: %s", err)
+		return errors.New("failed to marshal list to JSON")
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
+
+func listOutputTemplate(listTemplate *template.Template,
+	images *podman.Images,
+	containers *podman.Containers,
+	updates map[string]bool) error {
+
+	for _, image := range getListImages(images, updates) {
+		if err := listTemplate.Execute(os.Stdout, image); err != nil {
+			return fmt.Errorf("failed to execute template for image %s: %w", image.ID, err)
+		}
+
+		fmt.Println()
+	}
+
+	for _, container := range getListContainers(containers, updates) {
+		if err := listTemplate.Execute(os.Stdout, container); err != nil {
+			return fmt.Errorf("failed to execute template for container %s: %w", container.ID, err)
+		}
+
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func parseListTemplate(format string) (*template.Template, error) {
+	if !strings.Contains(format, "{{") {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--format'\n")
+		fmt.Fprintf(&builder, "Supported values are 'json', 'table' and Go templates.\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return nil, errors.New(errMsg)
+	}
+
+	funcs := template.FuncMap{
+		"json": func(value interface{}) (string, error) {
+			data, err := json.Marshal(value)
+			return string(data), err
+		},
+	}
+
+	listTemplate, err := template.New("list").Funcs(funcs).Parse(format)
+	if err != nil {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--format'\n")
+		fmt.Fprintf(&builder, "%s\n", err)
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return nil, errors.New(errMsg)
+	}
+
+	return listTemplate, nil
+}
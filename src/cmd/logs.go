@@ -0,0 +1,205 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsFlags struct {
+		follow bool
+		raw    bool
+		since  string
+	}
+)
+
+var logsCmd = &cobra.Command{
+	Use:               "logs",
+	Short:             "Show the logs of the entry point of a Toolbx container",
+	RunE:              logs,
+	ValidArgsFunction: completionContainerNamesFiltered,
+}
+
+func init() {
+	flags := logsCmd.Flags()
+
+	flags.BoolVarP(&logsFlags.follow, "follow", "f", false, "Keep showing new logs as they are written")
+
+	flags.BoolVar(&logsFlags.raw, "raw", false, "Show the logs exactly as written by the entry point")
+
+	flags.StringVar(&logsFlags.since,
+		"since",
+		"",
+		"Only show logs written after TIME, or during the last DURATION")
+
+	logsCmd.SetHelpFunc(logsHelp)
+	rootCmd.AddCommand(logsCmd)
+}
+
+func logs(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			return errors.New("this is not a Toolbx container")
+		}
+
+		exitCode, err := utils.ForwardToHost()
+		return &exitError{exitCode, err}
+	}
+
+	if len(args) > 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "too many arguments for \"logs\"\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	since := time.Unix(-1, 0)
+
+	if cmd.Flag("since").Changed {
+		var err error
+		since, err = parseLogsSince(logsFlags.since)
+		if err != nil {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "invalid argument for '--since'\n")
+			fmt.Fprintf(&builder, "Use a duration like 10m or a time like 2006-01-02T15:04:05Z.\n")
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+	}
+
+	container := utils.ContainerNameDefault
+	if len(args) != 0 {
+		container = args[0]
+	}
+
+	logrus.Debugf("Inspecting container %s", container)
+
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		logrus.Debugf("Inspecting container %s failed: %s", container, err)
+		err := createErrorContainerNotFound(container)
+		return err
+	}
+
+	if !containerObj.IsToolbx() {
+		return fmt.Errorf("%s is not a Toolbx container", container)
+	}
+
+	if err := showLogs(container, logsFlags.follow, since, logsFlags.raw); err != nil {
+		logrus.Debugf("Reading logs from container %s failed: %s", container, err)
+		return fmt.Errorf("failed to read logs from container %s", container)
+	}
+
+	return nil
+}
+
+func logsHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-logs"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func parseLogsSince(since string) (time.Time, error) {
+	if duration, err := time.ParseDuration(since); err == nil {
+		if duration < 0 {
+			return time.Time{}, fmt.Errorf("duration %s is negative", since)
+		}
+
+		sinceTime := time.Now().Add(-duration)
+		return sinceTime, nil
+	}
+
+	sinceTime, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return sinceTime, nil
+}
+
+func showLogs(container string, follow bool, since time.Time, raw bool) error {
+	reader, writer := io.Pipe()
+
+	go func() {
+		ctx := context.Background()
+		err := podman.LogsContext(ctx, container, follow, since, writer)
+		writer.CloseWithError(err)
+	}()
+
+	defer reader.Close()
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if raw {
+			fmt.Println(line)
+			continue
+		}
+
+		log, err := parseEntryPointLog(line)
+		if err != nil {
+			var errEntryPoint *entryPointError
+			if !errors.As(err, &errEntryPoint) {
+				logrus.Debugf("Parsing entry point log failed: %s", err)
+			}
+
+			fmt.Println(line)
+			continue
+		}
+
+		logLevelString := strings.ToUpper(log.level.String())
+		fmt.Printf("%-7s %s\n", logLevelString, log.msg)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
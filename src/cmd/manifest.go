@@ -0,0 +1,383 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// manifest describes a Toolbx container, so that it can be created the same
+// way on different hosts.
+type manifest struct {
+	Name    string `mapstructure:"name"`
+	Distro  string `mapstructure:"distro"`
+	Image   string `mapstructure:"image"`
+	Release string `mapstructure:"release"`
+
+	Env             []string `mapstructure:"env"`
+	HomeShares      []string `mapstructure:"home-shares"`
+	IdleTimeout     *uint    `mapstructure:"idle-timeout"`
+	IsolatedHome    bool     `mapstructure:"isolated-home"`
+	IsolatedXDGDirs *bool    `mapstructure:"isolated-xdg-dirs"`
+	Mounts          []string `mapstructure:"mounts"`
+	Volumes         []string `mapstructure:"volumes"`
+
+	// Applied to the container after it's created, and again when they
+	// change
+	Packages []string `mapstructure:"packages"`
+	Setup    []string `mapstructure:"setup"`
+
+	dir string
+}
+
+const (
+	manifestLabel = "com.github.containers.toolbox.manifest"
+
+	// Created inside the container with the provisionDigest of the
+	// manifest once the packages were installed and the setup commands run
+	manifestProvisionedStamp = "/var/lib/toolbox/manifest-provisioned"
+
+	installPackagesScript = `if command -v dnf >/dev/null 2>&1; then
+	exec dnf --assumeyes install "$@"
+elif command -v apt-get >/dev/null 2>&1; then
+	export DEBIAN_FRONTEND=noninteractive
+	apt-get update && exec apt-get --yes install "$@"
+elif command -v pacman >/dev/null 2>&1; then
+	exec pacman --sync --needed --noconfirm "$@"
+fi
+
+echo "no supported package manager found" >&2
+exit 127`
+)
+
+var (
+	manifestFilesDefault = []string{"toolbox.toml", "toolbox.yaml", "toolbox.yml"}
+
+	manifestTypes = []string{".toml", ".yaml", ".yml"}
+)
+
+// findManifest returns the first default manifest present in the current
+// directory.
+func findManifest() (string, error) {
+	for _, file := range manifestFilesDefault {
+		if utils.PathExists(file) {
+			return file, nil
+		}
+	}
+
+	return "", fmt.Errorf("none of %s found", strings.Join(manifestFilesDefault, ", "))
+}
+
+func readManifest(path string) (*manifest, error) {
+	ext := filepath.Ext(path)
+	if !slices.Contains(manifestTypes, ext) {
+		return nil, fmt.Errorf("file %s must end with .toml, .yaml or .yml", path)
+	}
+
+	if !utils.PathExists(path) {
+		return nil, fmt.Errorf("file %s not found", path)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+
+	if err := v.ReadInConfig(); err != nil {
+		logrus.Debugf("Reading manifest %s failed: %s", path, err)
+		return nil, fmt.Errorf("failed to parse file %s", path)
+	}
+
+	var m manifest
+	if err := v.UnmarshalExact(&m); err != nil {
+		logrus.Debugf("Decoding manifest %s failed: %s", path, err)
+		return nil, fmt.Errorf("file %s has unknown or invalid keys", path)
+	}
+
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("file %s is invalid: %w", path, err)
+	}
+
+	pathAbs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the absolute path to %s", path)
+	}
+
+	m.dir = filepath.Dir(pathAbs)
+	return &m, nil
+}
+
+// applyTo adds the options from the manifest to those from the configuration
+// file. The home directory must already be set, because shared paths are
+// mounted below it.
+func (m *manifest) applyTo(options *createOptions) error {
+	environ, err := parseEnvs(m.Env)
+	if err != nil {
+		return err
+	}
+
+	options.environ = append(options.environ, environ...)
+
+	if m.IdleTimeout != nil {
+		options.idleTimeout = *m.IdleTimeout
+	}
+
+	if m.IsolatedXDGDirs != nil {
+		options.isolatedXDGDirs = *m.IsolatedXDGDirs
+	}
+
+	for _, homeShare := range m.HomeShares {
+		if options.home == "" {
+			panic("home directory not specified")
+		}
+
+		volume, err := getHomeShareVolume(homeShare, options.home)
+		if err != nil {
+			return err
+		}
+
+		options.volumes = append(options.volumes, volume)
+	}
+
+	for _, volumeArg := range m.Volumes {
+		// Relative paths are looked up next to the manifest
+		if strings.HasPrefix(volumeArg, ".") {
+			volumeArg = filepath.Join(m.dir, volumeArg)
+		}
+
+		volume, err := parseVolume(volumeArg)
+		if err != nil {
+			return err
+		}
+
+		options.volumes = append(options.volumes, volume)
+	}
+
+	for _, mountArg := range m.Mounts {
+		volume, err := parseMount(mountArg)
+		if err != nil {
+			return err
+		}
+
+		options.volumes = append(options.volumes, volume)
+	}
+
+	options.manifestDigest = m.digest()
+	return nil
+}
+
+// getCreateOptions returns the options for creating the container from the
+// manifest, on top of those from the configuration file.
+func (m *manifest) getCreateOptions(container string) (*createOptions, error) {
+	options, err := getCreateOptionsFromConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if m.IsolatedHome {
+		options.home = getIsolatedHomeDir(container)
+	}
+
+	if err := m.applyTo(options); err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
+
+// digest identifies the parts of the manifest that can only be applied by
+// creating the container again.
+func (m *manifest) digest() string {
+	fixed := *m
+	fixed.Name = ""
+	fixed.Packages = nil
+	fixed.Setup = nil
+
+	data, err := json.Marshal(fixed)
+	if err != nil {
+		panicMsg := fmt.Sprintf("failed to marshal manifest to JSON: %s", err)
+		panic(panicMsg)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// provisionDigest identifies the parts of the manifest that are applied to
+// the container without creating it again.
+func (m *manifest) provisionDigest() string {
+	provisioned := struct {
+		Packages []string
+		Setup    []string
+	}{m.Packages, m.Setup}
+
+	data, err := json.Marshal(provisioned)
+	if err != nil {
+		panicMsg := fmt.Sprintf("failed to marshal manifest to JSON: %s", err)
+		panic(panicMsg)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// provision installs the packages and runs the setup commands from the
+// manifest inside the container, unless they were already applied to it.
+func (m *manifest) provision(container string) error {
+	if len(m.Packages) == 0 && len(m.Setup) == 0 {
+		return nil
+	}
+
+	digest := m.provisionDigest()
+	if getManifestProvisioned(container) == digest {
+		logrus.Debugf("Container %s is already provisioned with %s", container, digest)
+		return nil
+	}
+
+	if err := installPackages(container, m.Packages); err != nil {
+		return err
+	}
+
+	for _, setup := range m.Setup {
+		fmt.Printf("Running in container %s: %s\n", container, setup)
+
+		command := []string{"sh", "-c", setup}
+		if err := runCommand(container, false, "", "", 0, command, nil, false, false, true); err != nil {
+			logrus.Debugf("Running %s in container %s failed: %s", setup, container, err)
+			return fmt.Errorf("failed to run setup command in container %s: %s", container, setup)
+		}
+	}
+
+	if err := setManifestProvisioned(container, digest); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getManifestProvisioned returns the provisionDigest of the manifest that was
+// last applied to the container, or an empty string if there's none.
+func getManifestProvisioned(container string) string {
+	logrus.Debugf("Reading stamp %s in container %s", manifestProvisionedStamp, container)
+
+	if err := podman.Start(container, nil); err != nil {
+		logrus.Debugf("Starting container %s failed: %s", container, err)
+		return ""
+	}
+
+	logLevelString := podman.LogLevel.String()
+	args := []string{
+		"--log-level", logLevelString,
+		"exec",
+		"--user", currentUser.Username,
+		container,
+		"cat", manifestProvisionedStamp,
+	}
+
+	var stdout strings.Builder
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		logrus.Debugf("Reading stamp %s in container %s failed: %s", manifestProvisionedStamp, container, err)
+		return ""
+	}
+
+	digest := strings.TrimSpace(stdout.String())
+	return digest
+}
+
+// setManifestProvisioned records the provisionDigest of the manifest that was
+// applied to the container.
+func setManifestProvisioned(container, digest string) error {
+	logrus.Debugf("Creating stamp %s in container %s", manifestProvisionedStamp, container)
+
+	logLevelString := podman.LogLevel.String()
+	args := []string{
+		"--log-level", logLevelString,
+		"exec",
+		"--user", "root",
+		container,
+		"sh", "-c", "mkdir --parents \"$(dirname \"$1\")\" && echo \"$2\" >\"$1\"",
+		"sh", manifestProvisionedStamp, digest,
+	}
+
+	if err := shell.Run("podman", nil, nil, nil, args...); err != nil {
+		logrus.Debugf("Creating stamp %s in container %s failed: %s", manifestProvisionedStamp, container, err)
+		return fmt.Errorf("failed to create stamp %s in container %s", manifestProvisionedStamp, container)
+	}
+
+	return nil
+}
+
+// installPackages installs packages inside the container with whichever
+// package manager is available.
+func installPackages(container string, packages []string) error {
+	if len(packages) == 0 {
+		return nil
+	}
+
+	fmt.Printf("Installing packages in container %s: %s\n", container, strings.Join(packages, " "))
+
+	command := []string{"sudo", "sh", "-c", installPackagesScript, "sh"}
+	command = append(command, packages...)
+
+	if err := runCommand(container, false, "", "", 0, command, nil, false, false, true); err != nil {
+		logrus.Debugf("Installing packages in container %s failed: %s", container, err)
+		return fmt.Errorf("failed to install packages in container %s", container)
+	}
+
+	return nil
+}
+
+func (m *manifest) validate() error {
+	if m.Name != "" && !utils.IsContainerNameValid(m.Name) {
+		return fmt.Errorf("name %s must match '%s'", m.Name, utils.ContainerNameRegexp)
+	}
+
+	if m.Image != "" && m.Distro != "" {
+		return errors.New("distro and image cannot be used together")
+	}
+
+	if m.Image != "" && m.Release != "" {
+		return errors.New("image and release cannot be used together")
+	}
+
+	if m.IsolatedHome && m.IsolatedXDGDirs != nil && *m.IsolatedXDGDirs {
+		return errors.New("isolated-xdg-dirs cannot be used with isolated-home")
+	}
+
+	if len(m.HomeShares) > 0 && !m.IsolatedHome {
+		return errors.New("home-shares needs isolated-home")
+	}
+
+	for _, pkg := range m.Packages {
+		if pkg == "" || strings.HasPrefix(pkg, "-") {
+			return fmt.Errorf("package %s is invalid", pkg)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadManifest(t *testing.T) {
+	testCases := []struct {
+		name    string
+		file    string
+		content string
+		expect  *manifest
+		ok      bool
+	}{
+		{
+			name: "TOML",
+			file: "toolbox.toml",
+			content: `name = "project"
+distro = "fedora"
+release = "40"
+env = ["EDITOR=vim"]
+packages = ["gcc", "make"]
+setup = ["echo done"]
+`,
+			expect: &manifest{
+				Name:     "project",
+				Distro:   "fedora",
+				Release:  "40",
+				Env:      []string{"EDITOR=vim"},
+				Packages: []string{"gcc", "make"},
+				Setup:    []string{"echo done"},
+			},
+			ok: true,
+		},
+		{
+			name: "YAML",
+			file: "toolbox.yaml",
+			content: `image: registry.example.com/project/toolbox:latest
+isolated-home: true
+home-shares:
+  - .ssh
+`,
+			expect: &manifest{
+				Image:        "registry.example.com/project/toolbox:latest",
+				IsolatedHome: true,
+				HomeShares:   []string{".ssh"},
+			},
+			ok: true,
+		},
+		{
+			name:    "unknown key",
+			file:    "toolbox.toml",
+			content: "distribution = \"fedora\"\n",
+		},
+		{
+			name:    "unsupported extension",
+			file:    "toolbox.json",
+			content: "{}\n",
+		},
+		{
+			name:    "invalid name",
+			file:    "toolbox.toml",
+			content: "name = \"-project\"\n",
+		},
+		{
+			name:    "distro and image",
+			file:    "toolbox.toml",
+			content: "distro = \"fedora\"\nimage = \"fedora-toolbox:40\"\n",
+		},
+		{
+			name:    "home-shares without isolated-home",
+			file:    "toolbox.toml",
+			content: "home-shares = [\".ssh\"]\n",
+		},
+		{
+			name:    "package that is an option",
+			file:    "toolbox.toml",
+			content: "packages = [\"--nogpgcheck\"]\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, tc.file)
+			err := os.WriteFile(path, []byte(tc.content), 0644)
+			assert.NoError(t, err)
+
+			m, err := readManifest(path)
+			if !tc.ok {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			tc.expect.dir = dir
+			assert.Equal(t, tc.expect, m)
+		})
+	}
+}
+
+func TestManifestDigest(t *testing.T) {
+	m := manifest{
+		Name:     "project",
+		Distro:   "fedora",
+		Release:  "40",
+		Packages: []string{"gcc"},
+	}
+
+	digest := m.digest()
+	assert.Len(t, digest, 64)
+
+	m.Name = "other"
+	m.Packages = append(m.Packages, "make")
+	m.Setup = []string{"echo done"}
+	assert.Equal(t, digest, m.digest())
+
+	m.Release = "41"
+	assert.NotEqual(t, digest, m.digest())
+}
+
+func TestManifestProvisionDigest(t *testing.T) {
+	m := manifest{
+		Name:     "project",
+		Distro:   "fedora",
+		Release:  "40",
+		Packages: []string{"gcc"},
+	}
+
+	digest := m.provisionDigest()
+	assert.Len(t, digest, 64)
+
+	m.Name = "other"
+	m.Release = "41"
+	assert.Equal(t, digest, m.provisionDigest())
+
+	m.Setup = []string{"echo done"}
+	assert.NotEqual(t, digest, m.provisionDigest())
+}
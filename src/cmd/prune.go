@@ -0,0 +1,276 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/docker/go-units"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type pruneImage struct {
+	id    string
+	names []string
+	size  int64
+}
+
+var (
+	pruneFlags struct {
+		all    bool
+		dryRun bool
+	}
+)
+
+var pruneCmd = &cobra.Command{
+	Use:               "prune",
+	Short:             "Remove unused Toolbx images and stale runtime files",
+	RunE:              prune,
+	ValidArgsFunction: completionEmpty,
+}
+
+func init() {
+	flags := pruneCmd.Flags()
+
+	flags.BoolVarP(&pruneFlags.all,
+		"all",
+		"a",
+		false,
+		"Remove all Toolbx images that are not used by Toolbx containers")
+
+	flags.BoolVar(&pruneFlags.dryRun,
+		"dry-run",
+		false,
+		"Only show what would be removed and how much space would be freed")
+
+	pruneCmd.SetHelpFunc(pruneHelp)
+	rootCmd.AddCommand(pruneCmd)
+}
+
+func prune(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			return errors.New("this is not a Toolbx container")
+		}
+
+		exitCode, err := utils.ForwardToHost()
+		return &exitError{exitCode, err}
+	}
+
+	if len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "too many arguments for \"prune\"\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	logrus.Debug("Getting all images")
+
+	images, err := podman.GetImages(false)
+	if err != nil {
+		logrus.Debugf("Getting all images failed: %s", err)
+		return errors.New("failed to get images")
+	}
+
+	logrus.Debug("Getting all containers")
+
+	containers, err := podman.GetContainers()
+	if err != nil {
+		logrus.Debugf("Getting all containers failed: %s", err)
+		return errors.New("failed to get containers")
+	}
+
+	usedImageIDs := make(map[string]struct{})
+	for containers.Next() {
+		container := containers.Get()
+		usedImageIDs[container.ImageID()] = struct{}{}
+	}
+
+	pruneImages := getPruneImages(getPruneImagesFrom(images), usedImageIDs, pruneFlags.all)
+
+	staleRuntimeFiles, err := getStaleRuntimeFiles()
+	if err != nil {
+		logrus.Debugf("Looking up stale runtime files failed: %s", err)
+		return errors.New("failed to look up stale runtime files")
+	}
+
+	var failed bool
+	var reclaimed int64
+
+	for _, image := range pruneImages {
+		imageName := image.id
+		if len(image.names) != 0 {
+			imageName = image.names[0]
+		}
+
+		imageSize := units.HumanSize(float64(image.size))
+
+		if pruneFlags.dryRun {
+			fmt.Printf("Would remove image %s (%s)\n", imageName, imageSize)
+			reclaimed += image.size
+			continue
+		}
+
+		if err := podman.RemoveImage(image.id, false); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			failed = true
+			continue
+		}
+
+		fmt.Printf("Removed image %s (%s)\n", imageName, imageSize)
+		reclaimed += image.size
+	}
+
+	for _, staleRuntimeFile := range staleRuntimeFiles {
+		if pruneFlags.dryRun {
+			fmt.Printf("Would remove %s\n", staleRuntimeFile)
+			continue
+		}
+
+		logrus.Debugf("Removing stale runtime file %s", staleRuntimeFile)
+
+		if err := os.RemoveAll(staleRuntimeFile); err != nil {
+			logrus.Debugf("Removing stale runtime file %s failed: %s", staleRuntimeFile, err)
+			fmt.Fprintf(os.Stderr, "Error: failed to remove %s\n", staleRuntimeFile)
+			failed = true
+			continue
+		}
+
+		fmt.Printf("Removed %s\n", staleRuntimeFile)
+	}
+
+	// The sizes of images include the layers that they share with other
+	// images, so the total is only an upper bound
+	reclaimedHuman := units.HumanSize(float64(reclaimed))
+
+	if pruneFlags.dryRun {
+		fmt.Printf("Total space that would be reclaimed: at most %s\n", reclaimedHuman)
+	} else {
+		fmt.Printf("Total reclaimed space: at most %s\n", reclaimedHuman)
+	}
+
+	if failed {
+		return &exitError{1, nil}
+	}
+
+	return nil
+}
+
+func pruneHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-prune"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// getPruneImages returns the images that aren't used by any container, and
+// are either dangling, superseded by a newer release of the same image, or,
+// if all is set, any image.
+func getPruneImages(images []pruneImage, usedImageIDs map[string]struct{}, all bool) []pruneImage {
+	var ret []pruneImage
+
+	for _, image := range images {
+		if _, ok := usedImageIDs[image.id]; ok {
+			continue
+		}
+
+		if all || len(image.names) == 0 || isPruneImageSuperseded(image, images) {
+			ret = append(ret, image)
+		}
+	}
+
+	return ret
+}
+
+// getPruneImagesFrom lists every image only once, with all of its names.
+func getPruneImagesFrom(images *podman.Images) []pruneImage {
+	var ret []pruneImage
+
+	for _, mergedImage := range getMergedImages(images) {
+		image := mergedImage.image
+		ret = append(ret, pruneImage{id: image.ID(), names: mergedImage.names, size: image.Size()})
+	}
+
+	return ret
+}
+
+func isImageNameSuperseded(name string, images []pruneImage) bool {
+	distro, release := utils.GetDistroAndReleaseForImage(name)
+	if distro == "" || !utils.IsReleaseNumeric(release) {
+		return false
+	}
+
+	repository := name[:len(name)-len(utils.ImageReferenceGetTag(name))]
+
+	for _, other := range images {
+		for _, otherName := range other.names {
+			if !strings.HasPrefix(otherName, repository) {
+				continue
+			}
+
+			otherDistro, otherRelease := utils.GetDistroAndReleaseForImage(otherName)
+			if otherDistro != distro || !utils.IsReleaseNumeric(otherRelease) {
+				continue
+			}
+
+			if utils.CompareReleases(otherRelease, release) > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isPruneImageSuperseded returns true if every name of the image is for a
+// release of a supported distribution, and another image has the same name
+// with a newer release.
+func isPruneImageSuperseded(image pruneImage, images []pruneImage) bool {
+	if len(image.names) == 0 {
+		return false
+	}
+
+	for _, name := range image.names {
+		if !isImageNameSuperseded(name, images) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPruneImages(t *testing.T) {
+	images := []pruneImage{
+		{id: "dangling"},
+		{id: "fedora-38", names: []string{"registry.fedoraproject.org/fedora-toolbox:38"}},
+		{id: "fedora-39", names: []string{"registry.fedoraproject.org/fedora-toolbox:39"}},
+		{id: "fedora-40", names: []string{"registry.fedoraproject.org/fedora-toolbox:40"}},
+		{id: "fedora-rawhide", names: []string{"registry.fedoraproject.org/fedora-toolbox:rawhide"}},
+		{
+			id: "fedora-37-tagged",
+			names: []string{
+				"registry.fedoraproject.org/fedora-toolbox:37",
+				"localhost/my-toolbox:latest",
+			},
+		},
+		{id: "rhel-8.10", names: []string{"registry.access.redhat.com/ubi8/toolbox:8.10"}},
+		{id: "rhel-9.4", names: []string{"registry.access.redhat.com/ubi9/toolbox:9.4"}},
+		{id: "ubuntu-22.04", names: []string{"quay.io/toolbx/ubuntu-toolbox:22.04"}},
+		{id: "ubuntu-24.04", names: []string{"quay.io/toolbx/ubuntu-toolbox:24.04"}},
+		{id: "debian-12", names: []string{"quay.io/toolbx-images/debian-toolbox:12"}},
+		{id: "debian-13", names: []string{"quay.io/toolbx-images/debian-toolbox:13"}},
+		{id: "debian-testing", names: []string{"quay.io/toolbx-images/debian-toolbox:testing"}},
+		{id: "custom", names: []string{"localhost/custom:1"}},
+		{id: "custom-2", names: []string{"localhost/custom:2"}},
+	}
+
+	usedImageIDs := map[string]struct{}{
+		"fedora-38": {},
+	}
+
+	getIDs := func(images []pruneImage) []string {
+		var ids []string
+		for _, image := range images {
+			ids = append(ids, image.id)
+		}
+
+		return ids
+	}
+
+	pruneImages := getPruneImages(images, usedImageIDs, false)
+	assert.Equal(t, []string{"dangling", "fedora-39", "ubuntu-22.04", "debian-12"}, getIDs(pruneImages))
+
+	pruneImages = getPruneImages(images, usedImageIDs, true)
+	assert.Equal(t,
+		[]string{
+			"dangling",
+			"fedora-39",
+			"fedora-40",
+			"fedora-rawhide",
+			"fedora-37-tagged",
+			"rhel-8.10",
+			"rhel-9.4",
+			"ubuntu-22.04",
+			"ubuntu-24.04",
+			"debian-12",
+			"debian-13",
+			"debian-testing",
+			"custom",
+			"custom-2",
+		},
+		getIDs(pruneImages))
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pullFlags struct {
+		authFile string
+		distro   string
+		image    string
+		release  string
+	}
+)
+
+var pullCmd = &cobra.Command{
+	Use:               "pull",
+	Short:             "Download a Toolbx image without creating a container",
+	RunE:              pull,
+	ValidArgsFunction: completionEmpty,
+}
+
+func init() {
+	flags := pullCmd.Flags()
+
+	flags.StringVar(&pullFlags.authFile,
+		"authfile",
+		"",
+		"Path to a file with credentials for authenticating to the registry for private images")
+
+	flags.StringVarP(&pullFlags.distro,
+		"distro",
+		"d",
+		"",
+		"Download the image for a different operating system distribution than the host")
+
+	flags.StringVarP(&pullFlags.image,
+		"image",
+		"i",
+		"",
+		"Change the name of the image to download")
+
+	flags.StringVarP(&pullFlags.release,
+		"release",
+		"r",
+		"",
+		"Download the image for a different operating system release than the host")
+
+	pullCmd.SetHelpFunc(pullHelp)
+
+	if err := pullCmd.RegisterFlagCompletionFunc("distro", completionDistroNames); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+
+	if err := pullCmd.RegisterFlagCompletionFunc("image", completionImageNames); err != nil {
+		panicMsg := fmt.Sprintf("failed to register flag completion function: %v", err)
+		panic(panicMsg)
+	}
+
+	rootCmd.AddCommand(pullCmd)
+}
+
+func pull(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			return errors.New("this is not a Toolbx container")
+		}
+
+		exitCode, err := utils.ForwardToHost()
+		return &exitError{exitCode, err}
+	}
+
+	if len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "too many arguments for \"pull\"\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if cmd.Flag("distro").Changed && cmd.Flag("image").Changed {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "options --distro and --image cannot be used together\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if cmd.Flag("image").Changed && cmd.Flag("release").Changed {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "options --image and --release cannot be used together\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if cmd.Flag("authfile").Changed {
+		if !utils.PathExists(pullFlags.authFile) {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "file %s not found\n", pullFlags.authFile)
+			fmt.Fprintf(&builder, "'podman login' can be used to create the file.\n")
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+	}
+
+	if utils.ImageReferenceIsArchive(pullFlags.image) {
+		if _, err := loadImageFromArchive(pullFlags.image); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	_, image, release, err := resolveContainerAndImageNames("",
+		"",
+		pullFlags.distro,
+		pullFlags.image,
+		pullFlags.release)
+
+	if err != nil {
+		return err
+	}
+
+	// Unlike 'toolbox create', an image that is already present is
+	// downloaded again, so that it's brought up to date
+	pulled, err := pullImage(image,
+		release,
+		pullFlags.authFile,
+		"confirmation required to download image.",
+		true)
+
+	if err != nil {
+		return err
+	}
+
+	// The user declined to download the image
+	if !pulled {
+		return &exitError{1, nil}
+	}
+
+	return nil
+}
+
+func pullHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-pull"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
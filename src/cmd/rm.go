@@ -31,6 +31,7 @@ import (
 var (
 	rmFlags struct {
 		deleteAll   bool
+		filters     []string
 		forceDelete bool
 	}
 )
@@ -47,6 +48,11 @@ func init() {
 
 	flags.BoolVarP(&rmFlags.deleteAll, "all", "a", false, "Remove all Toolbx containers")
 
+	flags.StringArrayVar(&rmFlags.filters,
+		"filter",
+		nil,
+		"Remove all Toolbx containers that match the filter")
+
 	flags.BoolVarP(&rmFlags.forceDelete,
 		"force",
 		"f",
@@ -67,7 +73,21 @@ func rm(cmd *cobra.Command, args []string) error {
 		return &exitError{exitCode, err}
 	}
 
-	if rmFlags.deleteAll {
+	filters, err := parseFilters(rmFlags.filters)
+	if err != nil {
+		return err
+	}
+
+	if len(filters) != 0 && len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "option --filter cannot be used with container names\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if rmFlags.deleteAll || len(filters) != 0 {
 		logrus.Debug("Getting all containers")
 
 		toolboxContainers, err := podman.GetContainers()
@@ -76,6 +96,8 @@ func rm(cmd *cobra.Command, args []string) error {
 			return errors.New("failed to get containers")
 		}
 
+		toolboxContainers = toolboxContainers.Filter(filters.matchContainer)
+
 		for toolboxContainers.Next() {
 			container := toolboxContainers.Get()
 			containerID := container.ID()
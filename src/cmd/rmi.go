@@ -31,6 +31,7 @@ import (
 var (
 	rmiFlags struct {
 		deleteAll   bool
+		filters     []string
 		forceDelete bool
 	}
 )
@@ -47,6 +48,11 @@ func init() {
 
 	flags.BoolVarP(&rmiFlags.deleteAll, "all", "a", false, "Remove all Toolbx images")
 
+	flags.StringArrayVar(&rmiFlags.filters,
+		"filter",
+		nil,
+		"Remove all Toolbx images that match the filter")
+
 	flags.BoolVarP(&rmiFlags.forceDelete,
 		"force",
 		"f",
@@ -67,7 +73,30 @@ func rmi(cmd *cobra.Command, args []string) error {
 		return &exitError{exitCode, err}
 	}
 
-	if rmiFlags.deleteAll {
+	filters, err := parseFilters(rmiFlags.filters)
+	if err != nil {
+		return err
+	}
+
+	if key := filters.containerOnlyKey(); key != "" {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "filter %s cannot be used with images\n", key)
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if len(filters) != 0 && len(args) != 0 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "option --filter cannot be used with image names\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if rmiFlags.deleteAll || len(filters) != 0 {
 		logrus.Debug("Getting all images")
 
 		toolboxImages, err := podman.GetImages(false)
@@ -76,6 +105,8 @@ func rmi(cmd *cobra.Command, args []string) error {
 			return errors.New("failed to get images")
 		}
 
+		toolboxImages = toolboxImages.Filter(filters.matchImage)
+
 		for toolboxImages.Next() {
 			image := toolboxImages.Get()
 			imageID := image.ID()
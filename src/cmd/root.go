@@ -178,7 +178,8 @@ func preRun(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if err := utils.SetUpConfiguration(); err != nil {
+	forwardToHost := cmd.Name() != "init-container"
+	if err := utils.SetUpConfiguration(forwardToHost); err != nil {
 		return err
 	}
 
@@ -227,7 +228,7 @@ func migrate(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	if cmdName, completionCmdName := cmd.Name(), completionCmd.Name(); cmdName == completionCmdName {
+	if cmdName := cmd.Name(); cmdName == completionCmd.Name() || cmdName == doctorCmd.Name() {
 		logrus.Debugf("Migration not needed: command %s doesn't need it", cmdName)
 		return nil
 	}
@@ -420,7 +421,7 @@ func validateSubIDRanges(cmd *cobra.Command, args []string, user *user.User) (bo
 		return true, nil
 	}
 
-	if cmdName, completionCmdName := cmd.Name(), completionCmd.Name(); cmdName == completionCmdName {
+	if cmdName := cmd.Name(); cmdName == completionCmd.Name() || cmdName == doctorCmd.Name() {
 		logrus.Debugf("Look-up not needed: command %s doesn't need them", cmdName)
 		return true, nil
 	}
@@ -50,16 +50,24 @@ type entryPointError struct {
 	msg string
 }
 
+type entryPointLog struct {
+	level logrus.Level
+	msg   string
+}
+
 var (
 	runFlags struct {
 		container   string
 		distro      string
+		env         []string
 		preserveFDs uint
 		release     string
 	}
 
 	runFallbackCommands = [][]string{{"/bin/bash", "-l"}}
 	runFallbackWorkDirs = []string{"" /* $HOME */}
+
+	runEndOfLifeWarningShown bool
 )
 
 var runCmd = &cobra.Command{
@@ -85,6 +93,11 @@ func init() {
 		"",
 		"Run command inside a Toolbx container for a different operating system distribution than the host")
 
+	flags.StringArrayVar(&runFlags.env,
+		"env",
+		nil,
+		"Set an environment variable, as KEY=VALUE, only for this command")
+
 	flags.UintVar(&runFlags.preserveFDs,
 		"preserve-fds",
 		0,
@@ -141,6 +154,17 @@ func run(cmd *cobra.Command, args []string) error {
 
 	command := args
 
+	environ, err := parseEnvs(runFlags.env)
+	if err != nil {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "invalid argument for '--env'\n")
+		fmt.Fprintf(&builder, "%s\n", err)
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
 	container, image, release, err := resolveContainerAndImageNames(runFlags.container,
 		"--container",
 		runFlags.distro,
@@ -157,6 +181,7 @@ func run(cmd *cobra.Command, args []string) error {
 		release,
 		runFlags.preserveFDs,
 		command,
+		environ,
 		false,
 		false,
 		true); err != nil {
@@ -170,7 +195,7 @@ func runCommand(container string,
 	defaultContainer bool,
 	image, release string,
 	preserveFDs uint,
-	command []string,
+	command, environ []string,
 	emitEscapeSequence, fallbackToBash, pedantic bool) error {
 
 	if !pedantic {
@@ -225,7 +250,12 @@ func runCommand(container string,
 				return nil
 			}
 
-			if err := createContainer(container, image, release, "", false); err != nil {
+			options, err := getCreateOptionsFromConfig()
+			if err != nil {
+				return err
+			}
+
+			if err := createContainer(container, image, release, "", options, false); err != nil {
 				return err
 			}
 		} else if containersCount == 1 && defaultContainer {
@@ -271,6 +301,18 @@ func runCommand(container string,
 		return err
 	}
 
+	showEndOfLifeWarning(containerObj)
+
+	containerEnviron, err := getContainerEnviron(containerObj)
+	if err != nil {
+		return err
+	}
+
+	homeEnviron, err := getContainerHomeEnviron(containerObj)
+	if err != nil {
+		return err
+	}
+
 	var cdiEnviron []string
 
 	cdiSpecForNvidia, err := nvidia.GenerateCDISpec()
@@ -295,6 +337,7 @@ func runCommand(container string,
 	}
 
 	startContainerTimestamp := time.Unix(-1, 0)
+	started := false
 
 	if entryPointPID <= 0 {
 		if cdiSpecForNvidia != nil {
@@ -308,7 +351,12 @@ func runCommand(container string,
 			}
 		}
 
+		if err := runHook(hookPreStart, containerObj); err != nil {
+			return err
+		}
+
 		startContainerTimestamp = time.Now()
+		started = true
 
 		logrus.Debugf("Starting container %s", container)
 		if err := startContainer(container); err != nil {
@@ -346,20 +394,62 @@ func runCommand(container string,
 
 	logrus.Debugf("Container %s is initialized", container)
 
-	environ := append(cdiEnviron, p11KitServerEnviron...)
-	if err := runCommandWithFallbacks(container,
+	if started {
+		if err := runHook(hookPostStart, containerObj); err != nil {
+			return err
+		}
+	}
+
+	sessionFile, err := registerSession(entryPointPID)
+	if err != nil {
+		return err
+	}
+
+	defer unregisterSession(sessionFile)
+
+	// Later entries take precedence over earlier ones
+	var execEnviron []string
+	execEnviron = append(execEnviron, cdiEnviron...)
+	execEnviron = append(execEnviron, p11KitServerEnviron...)
+	execEnviron = append(execEnviron, homeEnviron...)
+	execEnviron = append(execEnviron, containerEnviron...)
+	execEnviron = append(execEnviron, environ...)
+
+	if err := runHook(hookPreEnter, containerObj); err != nil {
+		return err
+	}
+
+	// Containers with a separate home directory don't have the one from the
+	// host, so the working directory falls back to their own
+	homeDir := containerObj.Labels()[homeLabel]
+	if homeDir == "" {
+		homeDir = getCurrentUserHomeDir()
+	}
+
+	errRunCommand := runCommandWithFallbacks(container,
+		homeDir,
 		preserveFDs,
 		command,
-		environ,
+		execEnviron,
 		emitEscapeSequence,
-		fallbackToBash); err != nil {
-		return err
+		fallbackToBash)
+
+	if err := runHook(hookPostExit, containerObj); err != nil {
+		if errRunCommand == nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+	}
+
+	if errRunCommand != nil {
+		return errRunCommand
 	}
 
 	return nil
 }
 
-func runCommandWithFallbacks(container string,
+func runCommandWithFallbacks(container, homeDir string,
 	preserveFDs uint,
 	command, environ []string,
 	emitEscapeSequence, fallbackToBash bool) error {
@@ -453,7 +543,7 @@ func runCommandWithFallbacks(container string,
 
 					workDir = runFallbackWorkDirs[runFallbackWorkDirsIndex]
 					if workDir == "" {
-						workDir = getCurrentUserHomeDir()
+						workDir = homeDir
 					}
 
 					fmt.Fprintf(os.Stderr, "Using %s instead.\n", workDir)
@@ -622,7 +712,11 @@ func ensureContainerIsInitialized(container string, entryPointPID int, timestamp
 	}
 
 	logrus.Debugf("Setting up initialization timeout for container %s", container)
-	initializedTimeout := time.NewTimer(25 * time.Second)
+
+	// Restarted whenever the entry point logs something, so that slow init
+	// hooks don't cause a timeout as long as they are making progress
+	const initializedTimeoutDuration = 25 * time.Second
+	initializedTimeout := time.NewTimer(initializedTimeoutDuration)
 	defer initializedTimeout.Stop()
 
 	logrus.Debugf("Following logs for container %s", container)
@@ -714,6 +808,17 @@ func ensureContainerIsInitialized(container string, entryPointPID int, timestamp
 				return fmt.Errorf("failed to initialize container %s", container)
 			}
 		case line, ok := <-logsCh:
+			if ok {
+				if !initializedTimeout.Stop() {
+					select {
+					case <-initializedTimeout.C:
+					default:
+					}
+				}
+
+				initializedTimeout.Reset(initializedTimeoutDuration)
+			}
+
 			collectEntryPointErrorFn := func(err error) {
 				if !errors.Is(errReceivedFromEntryPoint, err) {
 					errReceivedFromEntryPoint = errors.Join(errReceivedFromEntryPoint, err)
@@ -896,6 +1001,92 @@ func isUsePollingSet() bool {
 	return true
 }
 
+// parseEntryPointLog parses a line logged by the entry point through logrus.
+// Lines that weren't logged through logrus are returned as an entryPointError,
+// because the entry point prints its fatal errors directly.
+func parseEntryPointLog(line string) (*entryPointLog, error) {
+	var log entryPointLog
+	var logLevelFound bool
+
+	reader := strings.NewReader(line)
+	decoder := logfmt.NewDecoder(reader)
+
+	if decoder.ScanRecord() {
+		for decoder.ScanKeyval() {
+			value := decoder.Value()
+			valueString := string(value)
+
+			switch key := decoder.Key(); string(key) {
+			case "level":
+				logLevelFound = true
+
+				var err error
+				log.level, err = logrus.ParseLevel(valueString)
+				if err != nil {
+					logrus.Debugf("Parsing entry point log-level %s failed: %s",
+						valueString,
+						err)
+					log.level = logrus.DebugLevel
+				}
+			case "msg":
+				log.msg = valueString
+			}
+		}
+	}
+
+	if err := decoder.Err(); err != nil {
+		return nil, err
+	}
+
+	if !logLevelFound {
+		errMsg, _ := strings.CutPrefix(line, "Error: ")
+		return nil, &entryPointError{errMsg}
+	}
+
+	return &log, nil
+}
+
+func registerSession(entryPointPID int) (*os.File, error) {
+	sessionsDirectory, err := utils.GetSessionsDirectory(entryPointPID, currentUser)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Debugf("Creating sessions directory %s", sessionsDirectory)
+
+	if err := os.MkdirAll(sessionsDirectory, 0700); err != nil {
+		logrus.Debugf("Creating sessions directory %s failed: %s", sessionsDirectory, err)
+		return nil, errors.New("failed to create sessions directory")
+	}
+
+	pid := os.Getpid()
+	sessionBase := strconv.Itoa(pid)
+	session := filepath.Join(sessionsDirectory, sessionBase)
+	logrus.Debugf("Registering session %s", session)
+
+	sessionFile, err := utils.Flock(session, syscall.LOCK_EX)
+	if err != nil {
+		logrus.Debugf("Registering session %s failed: %s", session, err)
+
+		var errFlock *utils.FlockError
+
+		if errors.As(err, &errFlock) {
+			if errors.Is(err, utils.ErrFlockAcquire) {
+				err = utils.ErrFlockAcquire
+			} else if errors.Is(err, utils.ErrFlockCreate) {
+				err = utils.ErrFlockCreate
+			} else {
+				panicMsg := fmt.Sprintf("unexpected %T: %s", err, err)
+				panic(panicMsg)
+			}
+		}
+
+		return nil, err
+	}
+
+	return sessionFile, nil
+}
+
 func saveCDISpecTo(spec *specs.Spec, path string) error {
 	if path == "" {
 		panic("path not specified")
@@ -926,48 +1117,46 @@ func saveCDISpecTo(spec *specs.Spec, path string) error {
 	return nil
 }
 
-func showEntryPointLog(line string) error {
-	var logLevel logrus.Level
-	var logLevelFound bool
-	var logMsg string
+// showEndOfLifeWarning warns if the container's image is for a release that
+// reached its end of life. It's only meant for humans, so it's not shown if
+// the standard error stream isn't a terminal, and it's shown only once, even if
+// several commands are run, like when applying a manifest.
+func showEndOfLifeWarning(container podman.Container) {
+	if runEndOfLifeWarningShown || !term.IsTerminal(os.Stderr) {
+		return
+	}
 
-	reader := strings.NewReader(line)
-	decoder := logfmt.NewDecoder(reader)
+	image := container.Image()
+	endOfLife, replacement := getImageEndOfLife(image)
+	if endOfLife.IsZero() {
+		return
+	}
 
-	if decoder.ScanRecord() {
-		for decoder.ScanKeyval() {
-			value := decoder.Value()
-			valueString := string(value)
+	runEndOfLifeWarningShown = true
 
-			switch key := decoder.Key(); string(key) {
-			case "level":
-				logLevelFound = true
+	name := container.Name()
+	endOfLifeString := endOfLife.Format(time.DateOnly)
+	fmt.Fprintf(os.Stderr, "Warning: container %s uses %s, which reached its end of life on %s\n",
+		name,
+		image,
+		endOfLifeString)
 
-				var err error
-				logLevel, err = logrus.ParseLevel(valueString)
-				if err != nil {
-					logrus.Debugf("Parsing entry point log-level %s failed: %s",
-						valueString,
-						err)
-					logLevel = logrus.DebugLevel
-				}
-			case "msg":
-				logMsg = valueString
-			}
-		}
+	if replacement != "" {
+		fmt.Fprintf(os.Stderr, "Consider upgrading it with '%s upgrade --release %s %s'.\n",
+			executableBase,
+			replacement,
+			name)
 	}
+}
 
-	if err := decoder.Err(); err != nil {
+func showEntryPointLog(line string) error {
+	log, err := parseEntryPointLog(line)
+	if err != nil {
 		return err
 	}
 
-	if !logLevelFound {
-		errMsg, _ := strings.CutPrefix(line, "Error: ")
-		return &entryPointError{errMsg}
-	}
-
 	logger := logrus.StandardLogger()
-	logger.Logf(logLevel, "> %s", logMsg)
+	logger.Logf(log.level, "> %s", log.msg)
 	return nil
 }
 
@@ -1110,6 +1299,17 @@ func startP11KitServer() ([]string, error) {
 	return serverEnviron, nil
 }
 
+func unregisterSession(sessionFile *os.File) {
+	session := sessionFile.Name()
+	logrus.Debugf("Unregistering session %s", session)
+
+	if err := os.Remove(session); err != nil {
+		logrus.Debugf("Removing session %s failed: %s", session, err)
+	}
+
+	sessionFile.Close()
+}
+
 func (err *entryPointError) Error() string {
 	return err.msg
 }
@@ -0,0 +1,148 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	stopFlags struct {
+		stopAll bool
+	}
+)
+
+var stopCmd = &cobra.Command{
+	Use:               "stop",
+	Short:             "Stop one or more running Toolbx containers",
+	RunE:              stop,
+	ValidArgsFunction: completionContainerNamesFiltered,
+}
+
+func init() {
+	flags := stopCmd.Flags()
+
+	flags.BoolVarP(&stopFlags.stopAll, "all", "a", false, "Stop all running Toolbx containers")
+
+	stopCmd.SetHelpFunc(stopHelp)
+	rootCmd.AddCommand(stopCmd)
+}
+
+func stop(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			return errors.New("this is not a Toolbx container")
+		}
+
+		exitCode, err := utils.ForwardToHost()
+		return &exitError{exitCode, err}
+	}
+
+	if stopFlags.stopAll {
+		logrus.Debug("Getting all containers")
+
+		toolboxContainers, err := podman.GetContainers()
+		if err != nil {
+			logrus.Debugf("Getting all containers failed: %s", err)
+			return errors.New("failed to get containers")
+		}
+
+		for toolboxContainers.Next() {
+			container := toolboxContainers.Get()
+			if status := container.Status(); status != "running" {
+				continue
+			}
+
+			containerName := container.Name()
+			if err := stopContainer(containerName); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+				continue
+			}
+		}
+	} else {
+		if len(args) == 0 {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "missing argument for \"stop\"\n")
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+
+		for _, container := range args {
+			containerObj, err := podman.InspectContainer(container)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to inspect container %s\n", container)
+				continue
+			}
+
+			if !containerObj.IsToolbx() {
+				fmt.Fprintf(os.Stderr, "Error: %s is not a Toolbx container\n", container)
+				continue
+			}
+
+			if err := stopContainer(container); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+				continue
+			}
+		}
+	}
+
+	return nil
+}
+
+func stopHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-stop"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+func stopContainer(container string) error {
+	logrus.Debugf("Stopping container %s", container)
+
+	var stderr strings.Builder
+	if err := podman.Stop(container, &stderr); err != nil {
+		errString := stderr.String()
+		logrus.Debugf("Stopping container %s failed: %s", container, errString)
+		return fmt.Errorf("failed to stop container %s", container)
+	}
+
+	return nil
+}
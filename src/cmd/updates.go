@@ -0,0 +1,260 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/skopeo"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/google/renameio/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	imageUpdatesCacheFile = "image-updates.json"
+
+	// How long the result of checking an image for updates is trusted by
+	// 'toolbox enter', before it's checked again in the background
+	imageUpdatesMaxAge = 24 * time.Hour
+
+	imageUpdatesTimeout = 30 * time.Second
+)
+
+type imageUpdate struct {
+	Name     string    `json:"name"`
+	Outdated bool      `json:"outdated"`
+	Checked  time.Time `json:"checked"`
+}
+
+// imageUpdates maps the IDs of local images to the result of the last check
+// for a newer image with the same name in its registry.
+type imageUpdates map[string]imageUpdate
+
+// checkImageUpdates checks the images, given as a map from their IDs to their
+// names, for updates in their registries and returns a map from their IDs to
+// whether a newer image is available. Images that aren't from a registry are
+// left out, and the names of those that failed to be checked are returned
+// separately. The results are cached for showImageUpdateHint.
+func checkImageUpdates(ctx context.Context, images map[string]string) (map[string]bool, []string) {
+	ret := make(map[string]bool)
+	if len(images) == 0 {
+		return ret, nil
+	}
+
+	cache := readImageUpdates()
+	registryDigests := make(map[string]string)
+	var failed []string
+
+	for id, name := range images {
+		if !isImageFromRegistry(name) {
+			logrus.Debugf("Checking image %s for updates: not from a registry", name)
+			continue
+		}
+
+		outdated, err := checkImageUpdate(ctx, id, name, registryDigests)
+		if err != nil {
+			logrus.Debugf("Checking image %s for updates failed: %s", name, err)
+			failed = append(failed, name)
+			continue
+		}
+
+		ret[id] = outdated
+		cache[id] = imageUpdate{Name: name, Outdated: outdated, Checked: time.Now()}
+	}
+
+	if err := writeImageUpdates(cache); err != nil {
+		logrus.Debugf("Caching the results of checking images for updates failed: %s", err)
+	}
+
+	sort.Strings(failed)
+	return ret, failed
+}
+
+// checkImageUpdate returns true if the digest of the image with the given
+// name in its registry doesn't match the local image with the given ID.
+// Digests from the registry are remembered in registryDigests, because
+// several local images can have the same name.
+func checkImageUpdate(ctx context.Context, id, name string, registryDigests map[string]string) (bool, error) {
+	registryDigest, ok := registryDigests[name]
+	if !ok {
+		logrus.Debugf("Getting the digest of image %s from its registry", name)
+
+		image, err := skopeo.Inspect(ctx, name)
+		if err != nil {
+			return false, err
+		}
+
+		if image.Digest == "" {
+			return false, errors.New("'skopeo inspect' did not have Digest")
+		}
+
+		registryDigest = image.Digest
+		registryDigests[name] = registryDigest
+	}
+
+	image, err := podman.InspectImage(id)
+	if err != nil {
+		return false, err
+	}
+
+	repoDigests := image.RepoDigests()
+	outdated := !isImageDigestIn(repoDigests, registryDigest)
+	return outdated, nil
+}
+
+func getImageUpdatesCachePath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(cacheDir, "toolbox", imageUpdatesCacheFile)
+	return path, nil
+}
+
+// isImageDigestIn returns true if digest is the digest of one of the
+// references in repoDigests, which are in the form NAME@DIGEST. For images
+// pulled through a manifest list, these contain the digests of both the list
+// and the image for this architecture.
+func isImageDigestIn(repoDigests []string, digest string) bool {
+	for _, repoDigest := range repoDigests {
+		i := strings.LastIndex(repoDigest, "@")
+		if i == -1 {
+			continue
+		}
+
+		if repoDigest[i+1:] == digest {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isImageFromRegistry(image string) bool {
+	domain := utils.ImageReferenceGetDomain(image)
+	if domain == "" || domain == "localhost" {
+		return false
+	}
+
+	return true
+}
+
+// readImageUpdates returns the cached results of checking images for updates.
+// A missing or broken cache is treated as empty.
+func readImageUpdates() imageUpdates {
+	cache := make(imageUpdates)
+
+	path, err := getImageUpdatesCachePath()
+	if err != nil {
+		logrus.Debugf("Reading the results of checking images for updates failed: %s", err)
+		return cache
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logrus.Debugf("Reading the results of checking images for updates failed: %s", err)
+		}
+
+		return cache
+	}
+
+	if err := json.Unmarshal(data, &cache); err != nil {
+		logrus.Debugf("Parsing the results of checking images for updates failed: %s", err)
+		return make(imageUpdates)
+	}
+
+	return cache
+}
+
+// showImageUpdateHint tells if the last check found a newer image for the
+// container. It never waits for the network. If the last check is missing or
+// too old, then the image is checked again in the background, and the result
+// is only used next time.
+func showImageUpdateHint(container string) {
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		logrus.Debugf("Showing hint about image updates for container %s: failed to inspect: %s",
+			container,
+			err)
+		return
+	}
+
+	id := containerObj.ImageID()
+	name := containerObj.Image()
+	if id == "" || !isImageFromRegistry(name) {
+		return
+	}
+
+	cache := readImageUpdates()
+	update, ok := cache[id]
+
+	if ok && update.Name == name && update.Outdated {
+		fmt.Fprintf(os.Stderr, "A newer image is available for container %s.\n", container)
+		fmt.Fprintf(os.Stderr, "Upgrade it with: %s upgrade %s\n", executableBase, container)
+	}
+
+	if ok && update.Name == name && time.Since(update.Checked) < imageUpdatesMaxAge {
+		return
+	}
+
+	logrus.Debugf("Checking image %s of container %s for updates in the background", name, container)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), imageUpdatesTimeout)
+		defer cancel()
+
+		images := map[string]string{id: name}
+		checkImageUpdates(ctx, images)
+	}()
+}
+
+// writeImageUpdates replaces the cache atomically, so that it's never seen
+// half-written by another process, or if the process is killed.
+func writeImageUpdates(cache imageUpdates) error {
+	path, err := getImageUpdatesCachePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	if err := renameio.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+
+	return nil
+}
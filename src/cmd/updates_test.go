@@ -0,0 +1,113 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsImageDigestIn(t *testing.T) {
+	repoDigests := []string{
+		"registry.fedoraproject.org/fedora-toolbox@sha256:1111",
+		"registry.fedoraproject.org/fedora-toolbox@sha256:2222",
+	}
+
+	testCases := []struct {
+		name        string
+		repoDigests []string
+		digest      string
+		expect      bool
+	}{
+		{
+			name:        "manifest list",
+			repoDigests: repoDigests,
+			digest:      "sha256:1111",
+			expect:      true,
+		},
+		{
+			name:        "image",
+			repoDigests: repoDigests,
+			digest:      "sha256:2222",
+			expect:      true,
+		},
+		{
+			name:        "outdated",
+			repoDigests: repoDigests,
+			digest:      "sha256:3333",
+			expect:      false,
+		},
+		{
+			name:        "no digests",
+			repoDigests: nil,
+			digest:      "sha256:1111",
+			expect:      false,
+		},
+		{
+			name:        "no name",
+			repoDigests: []string{"sha256:1111"},
+			digest:      "sha256:1111",
+			expect:      false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, isImageDigestIn(tc.repoDigests, tc.digest))
+		})
+	}
+}
+
+func TestIsImageFromRegistry(t *testing.T) {
+	assert.True(t, isImageFromRegistry("registry.fedoraproject.org/fedora-toolbox:40"))
+	assert.True(t, isImageFromRegistry("localhost:5000/fedora-toolbox:40"))
+	assert.False(t, isImageFromRegistry("localhost/fedora-toolbox-user:40"))
+	assert.False(t, isImageFromRegistry("fedora-toolbox:40"))
+}
+
+func TestImageUpdatesCache(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", dir)
+
+	assert.Empty(t, readImageUpdates())
+
+	checked := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	cache := imageUpdates{
+		"0e2ee1e3c8a8": {
+			Name:     "registry.fedoraproject.org/fedora-toolbox:40",
+			Outdated: true,
+			Checked:  checked,
+		},
+	}
+
+	err := writeImageUpdates(cache)
+	assert.NoError(t, err)
+	assert.Equal(t, cache, readImageUpdates())
+
+	entries, err := os.ReadDir(filepath.Join(dir, "toolbox"))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	path := filepath.Join(dir, "toolbox", imageUpdatesCacheFile)
+	err = os.WriteFile(path, []byte("{"), 0600)
+	assert.NoError(t, err)
+	assert.Empty(t, readImageUpdates())
+}
@@ -0,0 +1,542 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/podman"
+	"github.com/containers/toolbox/pkg/shell"
+	"github.com/containers/toolbox/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// createOptionsLabelValue holds the options of 'toolbox create' that aren't
+// already stored in other labels, so that the container can be created again.
+type createOptionsLabelValue struct {
+	IdleTimeout uint     `json:"idle-timeout,omitempty"`
+	InitHooks   []string `json:"init-hooks,omitempty"`
+	Mounts      []string `json:"mounts,omitempty"`
+	Volumes     []string `json:"volumes,omitempty"`
+}
+
+const (
+	createOptionsLabel = "com.github.containers.toolbox.create-options"
+
+	upgradeListPackagesScript = `if command -v dnf >/dev/null 2>&1; then
+	exec dnf --quiet repoquery --userinstalled --queryformat '%{name}\n'
+elif command -v apt-mark >/dev/null 2>&1; then
+	exec apt-mark showmanual
+elif command -v pacman >/dev/null 2>&1; then
+	exec pacman --query --quiet --explicit
+fi
+
+echo "no supported package manager found" >&2
+exit 127`
+
+	upgradeRollbackSuffix = "-rollback"
+)
+
+var (
+	upgradeFlags struct {
+		authFile     string
+		confirm      bool
+		force        bool
+		keepPackages bool
+		release      string
+		rollback     bool
+	}
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:               "upgrade",
+	Short:             "Create a Toolbx container again from a newer image, keeping its settings",
+	RunE:              upgrade,
+	ValidArgsFunction: completionContainerNamesFiltered,
+}
+
+func init() {
+	flags := upgradeCmd.Flags()
+
+	flags.StringVar(&upgradeFlags.authFile,
+		"authfile",
+		"",
+		"Path to a file with credentials for authenticating to the registry for private images")
+
+	flags.BoolVar(&upgradeFlags.confirm,
+		"confirm",
+		false,
+		"Keep the upgraded Toolbx container and remove the old one")
+
+	flags.BoolVar(&upgradeFlags.force,
+		"force",
+		false,
+		"Upgrade a Toolbx container even if some of its options would be lost")
+
+	flags.BoolVar(&upgradeFlags.keepPackages,
+		"keep-packages",
+		false,
+		"Install the packages that were installed in the old Toolbx container")
+
+	flags.StringVarP(&upgradeFlags.release,
+		"release",
+		"r",
+		"",
+		"Upgrade to a different operating system release")
+
+	flags.BoolVar(&upgradeFlags.rollback,
+		"rollback",
+		false,
+		"Remove the upgraded Toolbx container and restore the old one")
+
+	upgradeCmd.SetHelpFunc(upgradeHelp)
+	rootCmd.AddCommand(upgradeCmd)
+}
+
+func upgrade(cmd *cobra.Command, args []string) error {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			return errors.New("this is not a Toolbx container")
+		}
+
+		exitCode, err := utils.ForwardToHost()
+		return &exitError{exitCode, err}
+	}
+
+	if len(args) > 1 {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "too many arguments for \"upgrade\"\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if upgradeFlags.confirm && upgradeFlags.rollback {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "options --confirm and --rollback cannot be used together\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if upgradeFlags.confirm || upgradeFlags.rollback {
+		for _, option := range []string{"authfile", "force", "keep-packages", "release"} {
+			if !cmd.Flag(option).Changed {
+				continue
+			}
+
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "option --%s cannot be used with --confirm or --rollback\n", option)
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+	}
+
+	if cmd.Flag("authfile").Changed {
+		if !utils.PathExists(upgradeFlags.authFile) {
+			var builder strings.Builder
+			fmt.Fprintf(&builder, "file %s not found\n", upgradeFlags.authFile)
+			fmt.Fprintf(&builder, "'podman login' can be used to create the file.\n")
+			fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+			errMsg := builder.String()
+			return errors.New(errMsg)
+		}
+	}
+
+	container := utils.ContainerNameDefault
+	if len(args) != 0 {
+		container = args[0]
+	}
+
+	rollback := container + upgradeRollbackSuffix
+
+	if upgradeFlags.confirm {
+		return upgradeConfirm(container, rollback)
+	}
+
+	if upgradeFlags.rollback {
+		return upgradeRollback(container, rollback)
+	}
+
+	logrus.Debugf("Inspecting container %s", container)
+
+	containerObj, err := podman.InspectContainer(container)
+	if err != nil {
+		logrus.Debugf("Inspecting container %s failed: %s", container, err)
+		err := createErrorContainerNotFound(container)
+		return err
+	}
+
+	if !containerObj.IsToolbx() {
+		return fmt.Errorf("%s is not a Toolbx container", container)
+	}
+
+	if exists, _ := podman.ContainerExists(rollback); exists {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "container %s from an earlier upgrade already exists\n", rollback)
+		fmt.Fprintf(&builder, "Use the '--confirm' or '--rollback' option first.\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	if _, ok := containerObj.Labels()[createOptionsLabel]; !ok && !upgradeFlags.force {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "container %s doesn't record all the options it was created with\n", container)
+		fmt.Fprintf(&builder, "Its idle timeout, volumes, mounts and init hooks would be lost.\n")
+		fmt.Fprintf(&builder, "Use option '--force' to upgrade it anyway.\n")
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return errors.New(errMsg)
+	}
+
+	releaseChanged := cmd.Flag("release").Changed
+
+	image, release, err := getUpgradeImage(containerObj, releaseChanged)
+	if err != nil {
+		return err
+	}
+
+	options, err := getCreateOptionsFromContainer(containerObj)
+	if err != nil {
+		return err
+	}
+
+	pulled, err := upgradePullImage(image, release, releaseChanged)
+	if err != nil {
+		return err
+	}
+	if !pulled {
+		return nil
+	}
+
+	var packages []string
+
+	if upgradeFlags.keepPackages {
+		packages, err = getInstalledPackages(container)
+		if err != nil {
+			return err
+		}
+	}
+
+	logrus.Debugf("Stopping container %s", container)
+
+	if err := podman.Stop(container, nil); err != nil {
+		logrus.Debugf("Stopping container %s failed: %s", container, err)
+		return fmt.Errorf("failed to stop container %s", container)
+	}
+
+	if err := podman.RenameContainer(container, rollback); err != nil {
+		return err
+	}
+
+	if err := createContainer(container, image, release, upgradeFlags.authFile, options, false); err != nil {
+		if errRestore := upgradeRestore(container, rollback); errRestore != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", errRestore)
+		}
+
+		return err
+	}
+
+	if err := installPackages(container, packages); err != nil {
+		return err
+	}
+
+	enterCommand := getEnterCommand(container)
+
+	fmt.Printf("Upgraded container: %s\n", container)
+	fmt.Printf("Enter with: %s\n", enterCommand)
+	fmt.Printf("The old container was kept as %s.\n", rollback)
+	fmt.Printf("Keep the upgrade with: %s upgrade --confirm %s\n", executableBase, container)
+	fmt.Printf("Undo the upgrade with: %s upgrade --rollback %s\n", executableBase, container)
+	return nil
+}
+
+func upgradeHelp(cmd *cobra.Command, args []string) {
+	if utils.IsInsideContainer() {
+		if !utils.IsInsideToolboxContainer() {
+			fmt.Fprintf(os.Stderr, "Error: this is not a Toolbx container\n")
+			return
+		}
+
+		if _, err := utils.ForwardToHost(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return
+		}
+
+		return
+	}
+
+	if err := showManual("toolbox-upgrade"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return
+	}
+}
+
+// getCreateOptionsFromContainer returns the options that the container was
+// created with, from its labels.
+func getCreateOptionsFromContainer(container podman.Container) (*createOptions, error) {
+	environ, err := getContainerEnviron(container)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := container.Labels()
+
+	options := &createOptions{
+		environ:         environ,
+		home:            labels[homeLabel],
+		isolatedXDGDirs: labels[xdgDirsLabel] != "",
+		manifestDigest:  labels[manifestLabel],
+	}
+
+	createOptionsLabelString, ok := labels[createOptionsLabel]
+	if !ok {
+		logrus.Debugf("Container %s has no label %s", container.Name(), createOptionsLabel)
+		return options, nil
+	}
+
+	if err := unmarshalCreateOptionsLabel(createOptionsLabelString, options); err != nil {
+		logrus.Debugf("Parsing label %s of container %s failed: %s", createOptionsLabel, container.Name(), err)
+		return nil, fmt.Errorf("failed to parse the options of container %s", container.Name())
+	}
+
+	return options, nil
+}
+
+// getInstalledPackages returns the packages that were explicitly installed
+// inside the container by the user.
+func getInstalledPackages(container string) ([]string, error) {
+	logrus.Debugf("Getting installed packages in container %s", container)
+
+	if err := podman.Start(container, nil); err != nil {
+		return nil, fmt.Errorf("failed to start container %s", container)
+	}
+
+	logLevelString := podman.LogLevel.String()
+	args := []string{
+		"--log-level", logLevelString,
+		"exec",
+		"--user", "root",
+		container,
+		"sh", "-c", upgradeListPackagesScript,
+	}
+
+	var stdout strings.Builder
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		logrus.Debugf("Getting installed packages in container %s failed: %s", container, err)
+		return nil, fmt.Errorf("failed to get installed packages in container %s", container)
+	}
+
+	packages := strings.Fields(stdout.String())
+	return packages, nil
+}
+
+// getUpgradeImage returns the image and release to create the container
+// again with. It's the same image, unless a different release was requested.
+func getUpgradeImage(container podman.Container, releaseChanged bool) (string, string, error) {
+	image := container.Image()
+	distro, release := utils.GetDistroAndReleaseForImage(image)
+
+	if !releaseChanged {
+		if release == "" {
+			release = utils.ImageReferenceGetTag(image)
+		}
+
+		if release == "" {
+			release = "latest"
+		}
+
+		return image, release, nil
+	}
+
+	if distro == "" {
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "option --release cannot be used with container %s\n", container.Name())
+		fmt.Fprintf(&builder, "Image %s is not from a supported distribution.\n", image)
+		fmt.Fprintf(&builder, "Run '%s --help' for usage.", executableBase)
+
+		errMsg := builder.String()
+		return "", "", errors.New(errMsg)
+	}
+
+	_, image, release, err := resolveContainerAndImageNames(container.Name(),
+		"CONTAINER",
+		distro,
+		"",
+		upgradeFlags.release)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return image, release, nil
+}
+
+// isImageLocalOnly returns whether the image can only be found in local
+// storage, because it doesn't name a registry to pull it from again.
+func isImageLocalOnly(image string) bool {
+	if utils.ImageReferenceCanBeID(image) || !utils.ImageReferenceHasDomain(image) {
+		return true
+	}
+
+	domain := utils.ImageReferenceGetDomain(image)
+	return domain == "localhost"
+}
+
+// upgradePullImage pulls the latest version of the image. Images that only
+// exist in local storage, like those loaded from archives, are used as they
+// are.
+func upgradePullImage(image, release string, releaseChanged bool) (bool, error) {
+	if !releaseChanged && isImageLocalOnly(image) {
+		logrus.Debugf("Image %s can't be pulled, using it from local storage", image)
+		return true, nil
+	}
+
+	pulled, err := pullImage(image,
+		release,
+		upgradeFlags.authFile,
+		"image required to upgrade Toolbx container.",
+		true)
+
+	if err == nil || releaseChanged {
+		return pulled, err
+	}
+
+	if _, errExists := podman.ImageExists(image); errExists != nil {
+		return false, err
+	}
+
+	logrus.Debugf("Pulling image %s failed: %s", image, err)
+	fmt.Fprintf(os.Stderr, "Warning: failed to pull image %s, using it from local storage\n", image)
+	return true, nil
+}
+
+// marshalCreateOptionsLabel returns the argument for the label that stores
+// the options not found in other labels.
+func marshalCreateOptionsLabel(options *createOptions) (string, error) {
+	value := createOptionsLabelValue{
+		IdleTimeout: options.idleTimeout,
+		InitHooks:   options.initHooks,
+	}
+
+	for _, volume := range options.volumes {
+		switch volume.option {
+		case "--mount":
+			value.Mounts = append(value.Mounts, volume.arg)
+		case "--volume":
+			value.Volumes = append(value.Volumes, volume.arg)
+		default:
+			panicMsg := fmt.Sprintf("unexpected volume option %s", volume.option)
+			panic(panicMsg)
+		}
+	}
+
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		logrus.Debugf("Marshalling options to JSON failed: %s", err)
+		return "", errors.New("failed to marshal the options to JSON")
+	}
+
+	labelArg := createOptionsLabel + "=" + string(valueBytes)
+	return labelArg, nil
+}
+
+// unmarshalCreateOptionsLabel adds the options stored in the value of the
+// label to options.
+func unmarshalCreateOptionsLabel(labelValue string, options *createOptions) error {
+	var value createOptionsLabelValue
+	if err := json.Unmarshal([]byte(labelValue), &value); err != nil {
+		return err
+	}
+
+	options.idleTimeout = value.IdleTimeout
+	options.initHooks = value.InitHooks
+
+	for _, volumeArg := range value.Volumes {
+		volume, err := parseVolume(volumeArg)
+		if err != nil {
+			return err
+		}
+
+		options.volumes = append(options.volumes, volume)
+	}
+
+	for _, mountArg := range value.Mounts {
+		volume, err := parseMount(mountArg)
+		if err != nil {
+			return err
+		}
+
+		options.volumes = append(options.volumes, volume)
+	}
+
+	return nil
+}
+
+func upgradeConfirm(container, rollback string) error {
+	if exists, _ := podman.ContainerExists(rollback); !exists {
+		return fmt.Errorf("container %s was not upgraded", container)
+	}
+
+	if err := podman.RemoveContainer(rollback, true); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed container: %s\n", rollback)
+	return nil
+}
+
+// upgradeRestore puts the old container back in place of the new one.
+func upgradeRestore(container, rollback string) error {
+	if exists, _ := podman.ContainerExists(container); exists {
+		if err := podman.RemoveContainer(container, true); err != nil {
+			return err
+		}
+	}
+
+	if err := podman.RenameContainer(rollback, container); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func upgradeRollback(container, rollback string) error {
+	if exists, _ := podman.ContainerExists(rollback); !exists {
+		return fmt.Errorf("container %s was not upgraded", container)
+	}
+
+	if err := upgradeRestore(container, rollback); err != nil {
+		return err
+	}
+
+	fmt.Printf("Restored container: %s\n", container)
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateOptionsLabel(t *testing.T) {
+	dir := t.TempDir()
+
+	bindVolume, err := parseVolume(dir + ":/srv/data:ro")
+	assert.NoError(t, err)
+
+	mount, err := parseMount("type=tmpfs,destination=/var/cache/foo")
+	assert.NoError(t, err)
+
+	options := &createOptions{
+		idleTimeout: 30,
+		initHooks:   []string{"/srv/init.d"},
+		volumes:     []volume{bindVolume, mount},
+	}
+
+	labelArg, err := marshalCreateOptionsLabel(options)
+	assert.NoError(t, err)
+
+	labelValue, ok := strings.CutPrefix(labelArg, createOptionsLabel+"=")
+	assert.True(t, ok)
+
+	var optionsParsed createOptions
+	err = unmarshalCreateOptionsLabel(labelValue, &optionsParsed)
+	assert.NoError(t, err)
+	assert.Equal(t, options, &optionsParsed)
+
+	err = unmarshalCreateOptionsLabel("{}", &optionsParsed)
+	assert.NoError(t, err)
+
+	err = unmarshalCreateOptionsLabel("[", &optionsParsed)
+	assert.Error(t, err)
+}
+
+func TestIsImageLocalOnly(t *testing.T) {
+	testCases := []struct {
+		image  string
+		expect bool
+	}{
+		{"registry.fedoraproject.org/fedora-toolbox:40", false},
+		{"quay.io/toolbx/ubuntu-toolbox:24.04", false},
+		{"localhost/my-toolbox:latest", true},
+		{"my-toolbox", true},
+		{"ee2c1f2e8a4c", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.image, func(t *testing.T) {
+			localOnly := isImageLocalOnly(tc.image)
+			assert.Equal(t, tc.expect, localOnly)
+		})
+	}
+}
@@ -30,7 +30,9 @@ import (
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/containers/toolbox/pkg/podman"
 	"github.com/containers/toolbox/pkg/shell"
 	"github.com/containers/toolbox/pkg/utils"
 	"github.com/sirupsen/logrus"
@@ -40,6 +42,13 @@ import (
 type askForConfirmationPreFunc func() error
 type pollFunc func(error, []unix.PollFd) error
 
+// mergedImage is an image with all the names that podman.GetImages lists it
+// under.
+type mergedImage struct {
+	image podman.Image
+	names []string
+}
+
 var (
 	errClosed = errors.New("closed")
 
@@ -422,6 +431,70 @@ func getCurrentUserShell() (string, error) {
 	return passwdLineParts[passwdLinePartsCount-1], nil
 }
 
+// getContainerDataDir returns the directory on the host where data specific
+// to a Toolbx container, like a separate home directory, is kept.
+func getContainerDataDir(container string) string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		currentUserHomeDir := getCurrentUserHomeDir()
+		dataHome = filepath.Join(currentUserHomeDir, ".local", "share")
+	}
+
+	dataDir := filepath.Join(dataHome, "toolbox", container)
+	return dataDir
+}
+
+// getImageEndOfLife returns the date when the release of the distribution of
+// an image reached its end of life, and a newer release that is still
+// supported, if any. The zero time is returned for images that are still
+// supported, or not from a supported distribution.
+func getImageEndOfLife(image string) (time.Time, string) {
+	distro, release := utils.GetDistroAndReleaseForImage(image)
+	if distro == "" || release == "" {
+		return time.Time{}, ""
+	}
+
+	endOfLife, replacement := utils.GetReleaseEndOfLife(distro, release)
+	return endOfLife, replacement
+}
+
+// getIsolatedHomeDir returns the default home directory of a Toolbx container
+// created with 'toolbox create --isolated-home'.
+func getIsolatedHomeDir(container string) string {
+	dataDir := getContainerDataDir(container)
+	homeDir := filepath.Join(dataDir, "home")
+	return homeDir
+}
+
+// getMergedImages merges the entries that podman.GetImages returns for each
+// name of the same image, so that every image is seen only once.
+func getMergedImages(images *podman.Images) []mergedImage {
+	var ret []mergedImage
+	indices := make(map[string]int)
+
+	for images.Next() {
+		image := images.Get()
+		id := image.ID()
+
+		// podman.GetImages uses a placeholder for images without names
+		names := []string{}
+		if name := image.Name(); name != "<none>" {
+			names = append(names, name)
+		}
+
+		if i, ok := indices[id]; ok {
+			ret[i].names = append(ret[i].names, names...)
+			continue
+		}
+
+		indices[id] = len(ret)
+		ret = append(ret, mergedImage{image, names})
+	}
+
+	images.Reset()
+	return ret
+}
+
 func getUsageForCommonCommands() string {
 	var builder strings.Builder
 	builder.WriteString("create    Create a new Toolbx container\n")
@@ -432,6 +505,11 @@ func getUsageForCommonCommands() string {
 	return usage
 }
 
+func isImageEndOfLife(image string) bool {
+	endOfLife, _ := getImageEndOfLife(image)
+	return !endOfLife.IsZero()
+}
+
 func poll(pollFn pollFunc, eventFD int32, fds ...int32) error {
 	if len(fds) == 0 {
 		panic("file descriptors not specified")
@@ -0,0 +1,169 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/containers/toolbox/pkg/utils"
+)
+
+// volume is an extra volume or mount requested by the user, in a form that
+// can be passed to 'podman create'.
+type volume struct {
+	option      string
+	arg         string
+	destination string
+}
+
+var (
+	volumeNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+	// Mount kinds that bring in a whole hierarchy from the host, which must
+	// not be shadowed by mounting anything below them
+	volumeMountKindsExclusive = []string{"devices", "host", "runtime"}
+)
+
+// parseMount parses arguments for the --mount option of 'podman create', like
+// type=bind,source=/srv/data,destination=/srv/data.
+func parseMount(mountArg string) (volume, error) {
+	if mountArg == "" {
+		return volume{}, errors.New("mount must not be empty")
+	}
+
+	var destination string
+	var mountType string
+
+	for _, field := range strings.Split(mountArg, ",") {
+		key, value, _ := strings.Cut(field, "=")
+
+		switch key {
+		case "destination", "dst", "target":
+			destination = value
+		case "type":
+			mountType = value
+		}
+	}
+
+	if mountType == "" {
+		return volume{}, fmt.Errorf("mount %s is missing a type", mountArg)
+	}
+
+	if destination == "" {
+		return volume{}, fmt.Errorf("mount %s is missing a destination", mountArg)
+	}
+
+	if !filepath.IsAbs(destination) {
+		return volume{}, fmt.Errorf("destination %s must be an absolute path", destination)
+	}
+
+	destination = filepath.Clean(destination)
+	return volume{"--mount", mountArg, destination}, nil
+}
+
+// parseVolume parses arguments for the --volume option of 'podman create',
+// like /srv/data:/srv/data:ro or cache:/var/cache/foo. The source can either
+// be a path on the host or the name of a Podman volume.
+func parseVolume(volumeArg string) (volume, error) {
+	fields := strings.Split(volumeArg, ":")
+	if len(fields) < 2 || len(fields) > 3 {
+		return volume{}, fmt.Errorf("volume %s must be in the form SOURCE:DESTINATION[:OPTIONS]", volumeArg)
+	}
+
+	source := fields[0]
+	destination := fields[1]
+
+	if source == "" {
+		return volume{}, fmt.Errorf("volume %s is missing a source", volumeArg)
+	}
+
+	if strings.HasPrefix(source, "/") || strings.HasPrefix(source, ".") {
+		sourceAbs, err := filepath.Abs(source)
+		if err != nil {
+			return volume{}, fmt.Errorf("failed to get the absolute path to %s", source)
+		}
+
+		if !utils.PathExists(sourceAbs) {
+			return volume{}, fmt.Errorf("source %s not found", source)
+		}
+
+		source = sourceAbs
+	} else if !volumeNameRegexp.MatchString(source) {
+		return volume{}, fmt.Errorf("source %s must be a path or the name of a volume", source)
+	}
+
+	if !filepath.IsAbs(destination) {
+		return volume{}, fmt.Errorf("destination %s must be an absolute path", destination)
+	}
+
+	destination = filepath.Clean(destination)
+
+	fields[0] = source
+	fields[1] = destination
+	volumeArg = strings.Join(fields, ":")
+	return volume{"--volume", volumeArg, destination}, nil
+}
+
+// validateVolumes checks that the volumes neither clash with each other, nor
+// with the mounts that Toolbx already adds to its containers.
+func validateVolumes(volumes []volume, mountKinds map[string]string) error {
+	destinations := make(map[string]struct{})
+
+	for _, volume := range volumes {
+		if _, ok := destinations[volume.destination]; ok {
+			return fmt.Errorf("destination %s is used more than once", volume.destination)
+		}
+
+		destinations[volume.destination] = struct{}{}
+
+		for mountDestination, kind := range mountKinds {
+			if volume.destination == mountDestination {
+				return fmt.Errorf("destination %s is already used by Toolbx", volume.destination)
+			}
+
+			if isPathBelow(mountDestination, volume.destination) {
+				return fmt.Errorf("destination %s would hide %s used by Toolbx",
+					volume.destination,
+					mountDestination)
+			}
+
+			if isPathBelow(volume.destination, mountDestination) &&
+				slices.Contains(volumeMountKindsExclusive, kind) {
+				return fmt.Errorf("destination %s is inside %s used by Toolbx",
+					volume.destination,
+					mountDestination)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isPathBelow returns true if path is a descendant of parent. Both must be
+// clean absolute paths.
+func isPathBelow(path, parent string) bool {
+	if parent == "/" {
+		return path != "/"
+	}
+
+	return strings.HasPrefix(path, parent+"/")
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMount(t *testing.T) {
+	testCases := []struct {
+		name   string
+		arg    string
+		expect volume
+		ok     bool
+	}{
+		{
+			name: "bind mount",
+			arg:  "type=bind,source=/srv/data,destination=/srv/data",
+			expect: volume{
+				"--mount",
+				"type=bind,source=/srv/data,destination=/srv/data",
+				"/srv/data",
+			},
+			ok: true,
+		},
+		{
+			name: "tmpfs with target",
+			arg:  "type=tmpfs,target=/var/tmp/foo/",
+			expect: volume{
+				"--mount",
+				"type=tmpfs,target=/var/tmp/foo/",
+				"/var/tmp/foo",
+			},
+			ok: true,
+		},
+		{
+			name: "empty",
+			arg:  "",
+		},
+		{
+			name: "missing type",
+			arg:  "source=/srv/data,destination=/srv/data",
+		},
+		{
+			name: "missing destination",
+			arg:  "type=bind,source=/srv/data",
+		},
+		{
+			name: "relative destination",
+			arg:  "type=bind,source=/srv/data,dst=srv/data",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := parseMount(tc.arg)
+			if !tc.ok {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, v)
+		})
+	}
+}
+
+func TestParseVolume(t *testing.T) {
+	testCases := []struct {
+		name   string
+		arg    string
+		expect volume
+		ok     bool
+	}{
+		{
+			name:   "host path",
+			arg:    "/:/srv/data",
+			expect: volume{"--volume", "/:/srv/data", "/srv/data"},
+			ok:     true,
+		},
+		{
+			name:   "host path with options",
+			arg:    "/:/srv/data/:ro",
+			expect: volume{"--volume", "/:/srv/data:ro", "/srv/data"},
+			ok:     true,
+		},
+		{
+			name:   "named volume",
+			arg:    "cache:/var/cache/foo",
+			expect: volume{"--volume", "cache:/var/cache/foo", "/var/cache/foo"},
+			ok:     true,
+		},
+		{
+			name: "missing destination",
+			arg:  "/srv/data",
+		},
+		{
+			name: "missing source",
+			arg:  ":/srv/data",
+		},
+		{
+			name: "too many fields",
+			arg:  "/:/srv/data:ro:foo",
+		},
+		{
+			name: "missing host path",
+			arg:  "/nonexistent/path:/srv/data",
+		},
+		{
+			name: "invalid volume name",
+			arg:  "-cache:/var/cache/foo",
+		},
+		{
+			name: "relative destination",
+			arg:  "cache:var/cache/foo",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := parseVolume(tc.arg)
+			if !tc.ok {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, v)
+		})
+	}
+}
+
+func TestValidateVolumes(t *testing.T) {
+	mountKinds := map[string]string{
+		"/dev":                      "devices",
+		"/etc/profile.d/toolbox.sh": "toolbox.sh",
+		"/home/user":                "home",
+		"/run/host":                 "host",
+		"/run/user/1000":            "runtime",
+	}
+
+	testCases := []struct {
+		name         string
+		destinations []string
+		ok           bool
+	}{
+		{
+			name:         "outside Toolbx mounts",
+			destinations: []string{"/srv/data", "/var/cache/foo"},
+			ok:           true,
+		},
+		{
+			name:         "inside home",
+			destinations: []string{"/home/user/data"},
+			ok:           true,
+		},
+		{
+			name:         "same destination twice",
+			destinations: []string{"/srv/data", "/srv/data"},
+		},
+		{
+			name:         "same as a Toolbx mount",
+			destinations: []string{"/home/user"},
+		},
+		{
+			name:         "hides a Toolbx mount",
+			destinations: []string{"/etc"},
+		},
+		{
+			name:         "root",
+			destinations: []string{"/"},
+		},
+		{
+			name:         "inside the host",
+			destinations: []string{"/run/host/srv"},
+		},
+		{
+			name:         "inside the runtime directory",
+			destinations: []string{"/run/user/1000/foo"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var volumes []volume
+			for _, destination := range tc.destinations {
+				volumes = append(volumes, volume{"--volume", "cache:" + destination, destination})
+			}
+
+			err := validateVolumes(volumes, mountKinds)
+			if !tc.ok {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -25,10 +25,12 @@ import (
 
 type Container interface {
 	Created() string
+	CreatedTime() time.Time
 	EntryPoint() string
 	EntryPointPID() int
 	ID() string
 	Image() string
+	ImageID() string
 	IsToolbx() bool
 	Labels() map[string]string
 	Mounts() []string
@@ -39,10 +41,12 @@ type Container interface {
 
 type containerInspect struct {
 	created       string
+	createdTime   time.Time
 	entryPoint    string
 	entryPointPID int
 	id            string
 	image         string
+	imageID       string
 	labels        map[string]string
 	mounts        []string
 	name          string
@@ -51,10 +55,12 @@ type containerInspect struct {
 
 type containerPS struct {
 	created       string
+	createdTime   time.Time
 	entryPoint    string
 	entryPointPID int
 	id            string
 	image         string
+	imageID       string
 	labels        map[string]string
 	mounts        []string
 	names         []string
@@ -70,6 +76,10 @@ func (container *containerInspect) Created() string {
 	return container.created
 }
 
+func (container *containerInspect) CreatedTime() time.Time {
+	return container.createdTime
+}
+
 func (container *containerInspect) EntryPoint() string {
 	return container.entryPoint
 }
@@ -86,6 +96,10 @@ func (container *containerInspect) Image() string {
 	return container.image
 }
 
+func (container *containerInspect) ImageID() string {
+	return container.imageID
+}
+
 func (container *containerInspect) IsToolbx() bool {
 	if isToolbx(container.labels) {
 		return true
@@ -122,6 +136,7 @@ func (container *containerInspect) UnmarshalJSON(data []byte) error {
 		}
 		Created   time.Time
 		ID        string
+		Image     string
 		ImageName string
 		Mounts    []struct {
 			Destination string
@@ -145,9 +160,11 @@ func (container *containerInspect) UnmarshalJSON(data []byte) error {
 
 	created := raw.Created.Unix()
 	container.created = utils.HumanDuration(created)
+	container.createdTime = raw.Created
 
 	container.id = raw.ID
 	container.image = raw.ImageName
+	container.imageID = raw.Image
 	container.labels = raw.Config.Labels
 
 	for _, mount := range raw.Mounts {
@@ -165,6 +182,10 @@ func (container *containerPS) Created() string {
 	return container.created
 }
 
+func (container *containerPS) CreatedTime() time.Time {
+	return container.createdTime
+}
+
 func (container *containerPS) EntryPoint() string {
 	return container.entryPoint
 }
@@ -181,6 +202,10 @@ func (container *containerPS) Image() string {
 	return container.image
 }
 
+func (container *containerPS) ImageID() string {
+	return container.imageID
+}
+
 func (container *containerPS) IsToolbx() bool {
 	if isToolbx(container.labels) {
 		return true
@@ -211,16 +236,18 @@ func (container *containerPS) Status() string {
 
 func (container *containerPS) UnmarshalJSON(data []byte) error {
 	var raw struct {
-		Command []string
-		Created interface{}
-		ID      string
-		Image   string
-		Labels  map[string]string
-		Mounts  []string
-		Names   interface{}
-		PID     int
-		State   interface{}
-		Status  string
+		Command   []string
+		Created   interface{}
+		CreatedAt string
+		ID        string
+		Image     string
+		ImageID   string
+		Labels    map[string]string
+		Mounts    []string
+		Names     interface{}
+		PID       int
+		State     interface{}
+		Status    string
 	}
 
 	if err := json.Unmarshal(data, &raw); err != nil {
@@ -243,12 +270,15 @@ func (container *containerPS) UnmarshalJSON(data []byte) error {
 	switch value := raw.Created.(type) {
 	case string:
 		container.created = value
+		container.createdTime = parseCreatedAtTime(raw.CreatedAt)
 	case float64:
 		container.created = utils.HumanDuration(int64(value))
+		container.createdTime = time.Unix(int64(value), 0)
 	}
 
 	container.id = raw.ID
 	container.image = raw.Image
+	container.imageID = raw.ImageID
 	container.labels = raw.Labels
 	container.mounts = raw.Mounts
 
@@ -276,6 +306,43 @@ func (container *containerPS) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// parseCreatedAtTime parses the 'CreatedAt' field that accompanies the
+// human-readable 'Created' field of older Podman versions. It's either in RFC
+// 3339 format or in the format of Go's time.Time.String, and the zero time is
+// returned if it can't be parsed.
+func parseCreatedAtTime(createdAt string) time.Time {
+	if createdTime := parseCreatedTime(createdAt); !createdTime.IsZero() {
+		return createdTime
+	}
+
+	const layout = "2006-01-02 15:04:05.999999999 -0700 MST"
+	createdTime, err := time.Parse(layout, createdAt)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return createdTime
+}
+
+// Filter returns the containers for which match returns true, without
+// changing the position of the iterator.
+func (containers *Containers) Filter(match func(Container) bool) *Containers {
+	if containers == nil {
+		return nil
+	}
+
+	var data []containerPS
+
+	for i := range containers.data {
+		container := &containers.data[i]
+		if match(container) {
+			data = append(data, *container)
+		}
+	}
+
+	return &Containers{data, 0}
+}
+
 func (containers *Containers) Get() Container {
 	if containers.i < 1 {
 		panic("called Containers.Get() without calling Containers.Next()")
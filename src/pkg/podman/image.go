@@ -18,18 +18,22 @@ package podman
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/containers/toolbox/pkg/utils"
 )
 
 type Image interface {
 	Created() string
+	CreatedTime() time.Time
 	ID() string
 	IsToolbx() bool
 	Labels() map[string]string
 	Name() string
 	Names() []string
+	RepoDigests() []string
 	RepoTags() []string
+	Size() int64
 }
 
 type Images struct {
@@ -38,23 +42,48 @@ type Images struct {
 }
 
 type imageImages struct {
-	created  string
-	id       string
-	labels   map[string]string
-	names    []string
-	repoTags []string
+	created     string
+	createdTime time.Time
+	id          string
+	labels      map[string]string
+	names       []string
+	repoDigests []string
+	repoTags    []string
+	size        int64
 }
 
 type imageInspect struct {
 	created      string
+	createdTime  time.Time
 	id           string
 	labels       map[string]string
 	namesHistory []string
+	repoDigests  []string
 	repoTags     []string
+	size         int64
 }
 
 type imageSlice []imageImages
 
+// Filter returns the images for which match returns true, without changing
+// the position of the iterator.
+func (images *Images) Filter(match func(Image) bool) *Images {
+	if images == nil {
+		return nil
+	}
+
+	var data []imageImages
+
+	for i := range images.data {
+		image := &images.data[i]
+		if match(image) {
+			data = append(data, *image)
+		}
+	}
+
+	return &Images{data, 0}
+}
+
 func (images *Images) Get() Image {
 	if images == nil {
 		panic("called Images.Get() on a nil Images")
@@ -101,6 +130,10 @@ func (image *imageImages) Created() string {
 	return image.created
 }
 
+func (image *imageImages) CreatedTime() time.Time {
+	return image.createdTime
+}
+
 func (image *imageImages) flattenNames(fillNameWithID bool) []imageImages {
 	var ret []imageImages
 
@@ -173,6 +206,17 @@ func (image *imageImages) Names() []string {
 	return ret
 }
 
+func (image *imageImages) RepoDigests() []string {
+	if image.repoDigests == nil {
+		return nil
+	}
+
+	repoDigestsCount := len(image.repoDigests)
+	ret := make([]string, repoDigestsCount)
+	copy(ret, image.repoDigests)
+	return ret
+}
+
 func (image *imageImages) RepoTags() []string {
 	if image.repoTags == nil {
 		return nil
@@ -184,13 +228,19 @@ func (image *imageImages) RepoTags() []string {
 	return ret
 }
 
+func (image *imageImages) Size() int64 {
+	return image.size
+}
+
 func (image *imageImages) UnmarshalJSON(data []byte) error {
 	var raw struct {
-		Created  interface{}
-		ID       string
-		Labels   map[string]string
-		Names    []string
-		RepoTags []string
+		Created     interface{}
+		ID          string
+		Labels      map[string]string
+		Names       []string
+		RepoDigests []string
+		RepoTags    []string
+		Size        int64
 	}
 
 	if err := json.Unmarshal(data, &raw); err != nil {
@@ -203,14 +253,18 @@ func (image *imageImages) UnmarshalJSON(data []byte) error {
 	switch value := raw.Created.(type) {
 	case string:
 		image.created = value
+		image.createdTime = parseCreatedTime(value)
 	case float64:
 		image.created = utils.HumanDuration(int64(value))
+		image.createdTime = time.Unix(int64(value), 0)
 	}
 
 	image.id = raw.ID
 	image.labels = raw.Labels
 	image.names = raw.Names
+	image.repoDigests = raw.RepoDigests
 	image.repoTags = raw.RepoTags
+	image.size = raw.Size
 	return nil
 }
 
@@ -218,6 +272,10 @@ func (image *imageInspect) Created() string {
 	return image.created
 }
 
+func (image *imageInspect) CreatedTime() time.Time {
+	return image.createdTime
+}
+
 func (image *imageInspect) ID() string {
 	return image.id
 }
@@ -263,6 +321,17 @@ func (image *imageInspect) Names() []string {
 	return ret
 }
 
+func (image *imageInspect) RepoDigests() []string {
+	if image.repoDigests == nil {
+		return nil
+	}
+
+	repoDigestsCount := len(image.repoDigests)
+	ret := make([]string, repoDigestsCount)
+	copy(ret, image.repoDigests)
+	return ret
+}
+
 func (image *imageInspect) RepoTags() []string {
 	if image.repoTags == nil {
 		return nil
@@ -274,6 +343,10 @@ func (image *imageInspect) RepoTags() []string {
 	return ret
 }
 
+func (image *imageInspect) Size() int64 {
+	return image.size
+}
+
 func (image *imageInspect) UnmarshalJSON(data []byte) error {
 	var raw struct {
 		Created interface{}
@@ -282,7 +355,9 @@ func (image *imageInspect) UnmarshalJSON(data []byte) error {
 			Labels map[string]string
 		}
 		NamesHistory []string
+		RepoDigests  []string
 		RepoTags     []string
+		Size         int64
 	}
 
 	if err := json.Unmarshal(data, &raw); err != nil {
@@ -292,17 +367,33 @@ func (image *imageInspect) UnmarshalJSON(data []byte) error {
 	switch value := raw.Created.(type) {
 	case string:
 		image.created = value
+		image.createdTime = parseCreatedTime(value)
 	case float64:
 		image.created = utils.HumanDuration(int64(value))
+		image.createdTime = time.Unix(int64(value), 0)
 	}
 
 	image.id = raw.ID
 	image.labels = raw.Config.Labels
 	image.namesHistory = raw.NamesHistory
+	image.repoDigests = raw.RepoDigests
 	image.repoTags = raw.RepoTags
+	image.size = raw.Size
 	return nil
 }
 
+// parseCreatedTime parses the time when an image was created, if it's in RFC
+// 3339 format, and returns the zero time for human-readable strings like "5
+// minutes ago" that were used by older Podman versions
+func parseCreatedTime(created string) time.Time {
+	createdTime, err := time.Parse(time.RFC3339Nano, created)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return createdTime
+}
+
 func (images imageSlice) Len() int {
 	return len(images)
 }
@@ -25,6 +25,7 @@ import (
 	"io"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/HarryMichal/go-version"
@@ -109,6 +110,33 @@ func GetContainers() (*Containers, error) {
 	return &Containers{toolbxContainers, 0}, nil
 }
 
+// GetImageIDs returns the full IDs of all the images in the local storage,
+// including those that aren't Toolbx images.
+func GetImageIDs() (map[string]struct{}, error) {
+	var stdout bytes.Buffer
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "images", "--all", "--quiet", "--no-trunc"}
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]struct{})
+	output := strings.TrimSpace(stdout.String())
+
+	for _, id := range strings.Split(output, "\n") {
+		if id == "" {
+			continue
+		}
+
+		id = strings.TrimPrefix(id, "sha256:")
+		ids[id] = struct{}{}
+	}
+
+	return ids, nil
+}
+
 // GetImages is a wrapper function around `podman images --format json` command that returns all Toolbx images
 //
 // Parameter fillNameWithID is a boolean that indicates if the image names should be filled with the ID, when there
@@ -339,6 +367,31 @@ func Pull(imageName string, authfile string) error {
 	return nil
 }
 
+// PullFromArchive copies an image from an archive or a directory into the local
+// storage, and returns its ID
+//
+// reference uses one of the docker-archive, oci-archive or oci transports.
+// Nothing is downloaded from a registry.
+func PullFromArchive(reference string) (string, error) {
+	var stdout bytes.Buffer
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "pull", "--quiet", reference}
+
+	if err := shell.Run("podman", nil, &stdout, nil, args...); err != nil {
+		return "", err
+	}
+
+	output := strings.TrimSpace(stdout.String())
+	lines := strings.Split(output, "\n")
+	id := lines[len(lines)-1]
+	if id == "" {
+		return "", fmt.Errorf("failed to get the ID of the image from %s", reference)
+	}
+
+	return id, nil
+}
+
 func RemoveContainer(container string, forceDelete bool) error {
 	logrus.Debugf("Removing container %s", container)
 
@@ -405,6 +458,20 @@ func RemoveImage(image string, forceDelete bool) error {
 	return nil
 }
 
+// RenameContainer gives a new name to an existing container.
+func RenameContainer(container, newName string) error {
+	logrus.Debugf("Renaming container %s to %s", container, newName)
+
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "rename", container, newName}
+
+	if err := shell.Run("podman", nil, nil, nil, args...); err != nil {
+		return fmt.Errorf("failed to rename container %s to %s", container, newName)
+	}
+
+	return nil
+}
+
 func SetLogLevel(logLevel logrus.Level) {
 	LogLevel = logLevel
 }
@@ -420,6 +487,17 @@ func Start(container string, stderr io.Writer) error {
 	return nil
 }
 
+func Stop(container string, stderr io.Writer) error {
+	logLevelString := LogLevel.String()
+	args := []string{"--log-level", logLevelString, "stop", container}
+
+	if err := shell.Run("podman", nil, nil, stderr, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func SystemMigrate(ociRuntimeRequired string) error {
 	logLevelString := LogLevel.String()
 	args := []string{"--log-level", logLevelString, "system", "migrate"}
@@ -28,6 +28,7 @@ type Layer struct {
 	Size json.Number
 }
 type Image struct {
+	Digest     string
 	LayersData []Layer
 }
 
@@ -0,0 +1,207 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// debianAptDir is where the APT sources of the host are configured.
+	debianAptDir = "/etc/apt"
+
+	// debianCodenames maps the codenames of stable Debian releases to their
+	// numbers, which are used as the tags of the images.
+	debianCodenames = map[string]string{
+		"buster":   "10",
+		"bullseye": "11",
+		"bookworm": "12",
+		"trixie":   "13",
+		"forky":    "14",
+	}
+)
+
+// getAptSuitesDebian returns the suites of the APT sources of the host, from
+// both the one-line style sources.list(5) files and the deb822 style .sources
+// files. Files that can't be read are skipped.
+func getAptSuitesDebian() []string {
+	paths := []string{filepath.Join(debianAptDir, "sources.list")}
+
+	for _, pattern := range []string{"*.list", "*.sources"} {
+		matches, _ := filepath.Glob(filepath.Join(debianAptDir, "sources.list.d", pattern))
+		paths = append(paths, matches...)
+	}
+
+	var suites []string
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logrus.Debugf("Reading APT sources %s failed: %s", path, err)
+			continue
+		}
+
+		deb822 := strings.HasSuffix(path, ".sources")
+		suites = append(suites, parseAptSuitesDebian(string(data), deb822)...)
+	}
+
+	return suites
+}
+
+func getDefaultReleaseDebian() (string, error) {
+	osRelease, err := readOSRelease()
+	if err != nil {
+		return "", err
+	}
+
+	var suites []string
+	if osRelease["VERSION_ID"] == "" {
+		suites = getAptSuitesDebian()
+	}
+
+	release := getDefaultReleaseDebianFromOSRelease(osRelease, suites)
+	return release, nil
+}
+
+// getDefaultReleaseDebianFromOSRelease returns the release of a Debian host
+// from its os-release(5) fields and the suites of its APT sources. Debian
+// testing and unstable don't have a VERSION_ID, and share the same os-release
+// file, like PRETTY_NAME="Debian GNU/Linux trixie/sid" and
+// VERSION_CODENAME=trixie. Therefore, unstable is only recognized if the APT
+// sources follow 'sid' or 'unstable', without also following 'testing' or the
+// codename of testing.
+func getDefaultReleaseDebianFromOSRelease(osRelease map[string]string, suites []string) string {
+	if release := osRelease["VERSION_ID"]; release != "" {
+		return release
+	}
+
+	codename := osRelease["VERSION_CODENAME"]
+	if codename == "sid" {
+		return "unstable"
+	}
+
+	var followsTesting, followsUnstable bool
+
+	for _, suite := range suites {
+		switch suite {
+		case "sid", "unstable":
+			followsUnstable = true
+		case "testing", codename:
+			followsTesting = true
+		}
+	}
+
+	if followsUnstable && !followsTesting {
+		return "unstable"
+	}
+
+	return "testing"
+}
+
+func getFullyQualifiedImageDebian(image, release string) string {
+	imageFull := "quay.io/toolbx-images/" + image
+	return imageFull
+}
+
+func getP11KitClientPathsDebian() []string {
+	paths := []string{
+		"/usr/lib/aarch64-linux-gnu/pkcs11/p11-kit-client.so",
+		"/usr/lib/x86_64-linux-gnu/pkcs11/p11-kit-client.so",
+	}
+
+	return paths
+}
+
+func parseReleaseDebian(release string) (string, error) {
+	switch release {
+	case "sid", "unstable":
+		return "unstable", nil
+	case "testing":
+		return release, nil
+	}
+
+	if releaseN, ok := debianCodenames[release]; ok {
+		return releaseN, nil
+	}
+
+	releaseN, err := strconv.Atoi(release)
+	if err != nil {
+		logrus.Debugf("Parsing release %s as an integer failed: %s", release, err)
+		return "", &ParseReleaseError{"The release must be a number, a codename, 'testing' or 'unstable'."}
+	}
+
+	if releaseN <= 0 {
+		return "", &ParseReleaseError{"The release must be a positive integer."}
+	}
+
+	return release, nil
+}
+
+// parseAptSuitesDebian returns the suites of the Debian sources in the
+// contents of an APT sources file, which is either in the one-line style or
+// the deb822 style.
+func parseAptSuitesDebian(sources string, deb822 bool) []string {
+	var suites []string
+
+	for _, line := range strings.Split(sources, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if deb822 {
+			key, value, found := strings.Cut(line, ":")
+			if found && strings.EqualFold(key, "Suites") {
+				suites = append(suites, strings.Fields(value)...)
+			}
+
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 || (fields[0] != "deb" && fields[0] != "deb-src") {
+			continue
+		}
+
+		fields = fields[1:]
+
+		// Skip the options, like '[arch=amd64 signed-by=/path/to/key]'
+		if len(fields) != 0 && strings.HasPrefix(fields[0], "[") {
+			for len(fields) != 0 {
+				option := fields[0]
+				fields = fields[1:]
+				if strings.HasSuffix(option, "]") {
+					break
+				}
+			}
+		}
+
+		// The URI comes before the suite
+		if len(fields) < 2 {
+			continue
+		}
+
+		suites = append(suites, fields[1])
+	}
+
+	return suites
+}
@@ -0,0 +1,243 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// userDistroConfig is a [distro.NAME] section of the configuration for a
+// distribution that isn't built in.
+type userDistroConfig struct {
+	ContainerNamePrefix string   `mapstructure:"container-name-prefix"`
+	Image               string   `mapstructure:"image"`
+	P11KitClientPaths   []string `mapstructure:"p11-kit-client-paths"`
+	Registry            string   `mapstructure:"registry"`
+	ReleaseRegex        string   `mapstructure:"release-regex"`
+	ReleaseRequired     bool     `mapstructure:"release-required"`
+}
+
+const (
+	// Based on the tag grammar in:
+	// https://github.com/distribution/reference/blob/main/reference.go
+	releaseRegexpDefault = `[\w][\w.-]{0,127}`
+)
+
+var (
+	// userDistros are the names of the distributions that were added to
+	// supportedDistros from the configuration.
+	userDistros = make(map[string]struct{})
+)
+
+func newUserDistro(distro string, config *userDistroConfig) (Distro, error) {
+	if config.Image == "" {
+		return Distro{}, fmt.Errorf("distribution %s does not have an image", distro)
+	}
+
+	if config.Registry == "" {
+		return Distro{}, fmt.Errorf("distribution %s does not have a registry", distro)
+	}
+
+	containerNamePrefix := config.ContainerNamePrefix
+	if containerNamePrefix == "" {
+		containerNamePrefix = config.Image
+	}
+
+	if !IsContainerNameValid(containerNamePrefix) {
+		return Distro{},
+			fmt.Errorf("container name prefix %s for distribution %s is invalid", containerNamePrefix, distro)
+	}
+
+	releaseRegexpString := config.ReleaseRegex
+	if releaseRegexpString == "" {
+		releaseRegexpString = releaseRegexpDefault
+	}
+
+	releaseRegexp, err := regexp.Compile("^(" + releaseRegexpString + ")$")
+	if err != nil {
+		logrus.Debugf("Compiling release-regex for distribution %s failed: %s", distro, err)
+		return Distro{}, fmt.Errorf("release-regex for distribution %s is invalid", distro)
+	}
+
+	registry := strings.TrimSuffix(config.Registry, "/")
+	releaseRequired := config.ReleaseRequired
+	p11KitClientPaths := config.P11KitClientPaths
+
+	getDefaultRelease := func() (string, error) {
+		if !releaseRequired {
+			return "latest", nil
+		}
+
+		release, err := getHostVersionID()
+		if err != nil {
+			return "", err
+		}
+
+		return release, nil
+	}
+
+	getFullyQualifiedImage := func(image, release string) string {
+		imageFull := registry + "/" + image
+		return imageFull
+	}
+
+	getP11KitClientPaths := func() []string {
+		return p11KitClientPaths
+	}
+
+	parseRelease := func(release string) (string, error) {
+		if release == "" && !releaseRequired {
+			return "latest", nil
+		}
+
+		if !releaseRegexp.MatchString(release) {
+			hint := fmt.Sprintf("The release must match '%s'.", releaseRegexpString)
+			return "", &ParseReleaseError{hint}
+		}
+
+		return release, nil
+	}
+
+	distroObj := Distro{
+		containerNamePrefix,
+		config.Image,
+		releaseRequired,
+		getDefaultRelease,
+		getFullyQualifiedImage,
+		nil,
+		getP11KitClientPaths,
+		parseRelease,
+	}
+
+	return distroObj, nil
+}
+
+// setUpDistros reads the [distro.NAME] sections of the configuration. Those
+// for the built-in distributions can only change their registries and images,
+// while the others define new distributions that are added to
+// supportedDistros.
+func setUpDistros() error {
+	for distro := range userDistros {
+		delete(supportedDistros, distro)
+		delete(userDistros, distro)
+	}
+
+	containerNamePrefixDefault = builtInDefaults.containerNamePrefix
+	distroDefault = builtInDefaults.distro
+	releaseDefault = builtInDefaults.release
+
+	var distros []string
+	for distro := range viper.GetStringMap("distro") {
+		distros = append(distros, distro)
+	}
+
+	sort.Strings(distros)
+
+	for _, distro := range distros {
+		if _, supportedDistro := supportedDistros[distro]; supportedDistro {
+			if err := validateDistroOverrides(distro, true); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		v := viper.Sub("distro." + distro)
+		if v == nil {
+			return fmt.Errorf("configuration for distribution %s is not a section", distro)
+		}
+
+		var config userDistroConfig
+		if err := v.UnmarshalExact(&config); err != nil {
+			logrus.Debugf("Decoding configuration for distribution %s failed: %s", distro, err)
+			return fmt.Errorf("configuration for distribution %s has unknown or invalid keys", distro)
+		}
+
+		if err := validateDistroOverrides(distro, false); err != nil {
+			return err
+		}
+
+		distroObj, err := newUserDistro(distro, &config)
+		if err != nil {
+			return err
+		}
+
+		for otherDistro := range supportedDistros {
+			if isImageBasenameForDistro(otherDistro, distroObj.ImageBasename) {
+				return fmt.Errorf("distribution %s has the same image as %s", distro, otherDistro)
+			}
+		}
+
+		supportedDistros[distro] = distroObj
+		userDistros[distro] = struct{}{}
+	}
+
+	hostID, err := getHostID()
+	if err != nil {
+		return nil
+	}
+
+	if _, userDistro := userDistros[hostID]; !userDistro {
+		return nil
+	}
+
+	release, err := getDefaultReleaseForDistro(hostID)
+	if err != nil {
+		logrus.Debugf("Getting the default release for distribution %s failed: %s", hostID, err)
+		return nil
+	}
+
+	containerNamePrefixDefault = supportedDistros[hostID].ContainerNamePrefix
+	distroDefault = hostID
+	releaseDefault = release
+	return nil
+}
+
+// validateDistroOverrides checks the options of a [distro.NAME] section that
+// are also understood for the built-in distributions, which can't use any
+// others.
+func validateDistroOverrides(distro string, builtIn bool) error {
+	if builtIn {
+		for key := range viper.GetStringMap("distro." + distro) {
+			if key != "image" && key != "registry" {
+				return fmt.Errorf("option %s cannot be used for built-in distribution %s", key, distro)
+			}
+		}
+	}
+
+	if key := "distro." + distro + ".registry"; viper.IsSet(key) {
+		registry := strings.TrimSuffix(viper.GetString(key), "/")
+		if !ImageReferenceHasDomain(registry + "/") {
+			return fmt.Errorf("registry %s for distribution %s does not have a domain", registry, distro)
+		}
+	}
+
+	if key := "distro." + distro + ".image"; viper.IsSet(key) {
+		basename := viper.GetString(key)
+		if basename == "" || strings.ContainsAny(basename, "/:@") {
+			return fmt.Errorf("image %s for distribution %s is not a basename", basename, distro)
+		}
+	}
+
+	return nil
+}
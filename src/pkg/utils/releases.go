@@ -0,0 +1,178 @@
+/*
+ * Copyright © 2026 Red Hat Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// releaseLifetime is the support lifetime of a release of a distribution. A
+// release without an end of life is still supported.
+type releaseLifetime struct {
+	Release   string `json:"release"`
+	EndOfLife string `json:"end-of-life,omitempty"`
+
+	endOfLife time.Time
+}
+
+var (
+	// releasesData lists the known releases of the supported distributions
+	// with the dates when they reach their end of life. It's built into the
+	// binary and can't be overridden, so it must be updated when new
+	// releases come out or end-of-life dates change, and a stale copy only
+	// gets fixed by a new version of Toolbx. The releases must be written as
+	// returned by parseRelease, and distributions without fixed releases are
+	// left out.
+	//
+	//go:embed releases.json
+	releasesData []byte
+
+	releaseLifetimes map[string][]releaseLifetime
+)
+
+func init() {
+	var err error
+	releaseLifetimes, err = parseReleaseLifetimes(releasesData)
+	if err != nil {
+		panicMsg := fmt.Sprintf("failed to parse releases.json: %s", err)
+		panic(panicMsg)
+	}
+}
+
+// CompareReleases compares releases made of numbers separated by dots, like
+// '40', '9.4' or '24.04'. The result is negative if a is older than b, zero if
+// they are the same, and positive if a is newer than b. Other releases can't
+// be compared, and need to be ruled out with IsReleaseNumeric.
+func CompareReleases(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aPart, _ := strconv.Atoi(aParts[i])
+		bPart, _ := strconv.Atoi(bParts[i])
+		if aPart != bPart {
+			return aPart - bPart
+		}
+	}
+
+	return len(aParts) - len(bParts)
+}
+
+// IsReleaseNumeric returns true if the release can be compared with others
+// by CompareReleases, unlike rolling releases like 'rawhide' or 'latest'.
+func IsReleaseNumeric(release string) bool {
+	if release == "" {
+		return false
+	}
+
+	return strings.Trim(release, "0123456789.") == ""
+}
+
+// GetReleaseEndOfLife returns the date when the release of the distro reached
+// its end of life, and a newer release that is still supported, if any. The
+// zero time is returned if the release is still supported, or if nothing is
+// known about it. Only the lifetimes built into the binary from releases.json
+// are known.
+func GetReleaseEndOfLife(distro, release string) (time.Time, string) {
+	lifetimes, ok := releaseLifetimes[distro]
+	if !ok {
+		return time.Time{}, ""
+	}
+
+	return getReleaseEndOfLife(lifetimes, release, time.Now())
+}
+
+// getReleaseEndOfLife is like GetReleaseEndOfLife, but for the given lifetimes
+// and time. The suggested replacement is the newest supported release with the
+// same major version, or the newest supported release if there's none.
+func getReleaseEndOfLife(lifetimes []releaseLifetime, release string, now time.Time) (time.Time, string) {
+	var endOfLife time.Time
+
+	for _, lifetime := range lifetimes {
+		if lifetime.Release == release {
+			endOfLife = lifetime.endOfLife
+			break
+		}
+	}
+
+	if endOfLife.IsZero() || now.Before(endOfLife) {
+		return time.Time{}, ""
+	}
+
+	major, _, _ := strings.Cut(release, ".")
+	var replacement string
+	var replacementSameMajor string
+
+	for _, lifetime := range lifetimes {
+		if !lifetime.endOfLife.IsZero() && !now.Before(lifetime.endOfLife) {
+			continue
+		}
+
+		if CompareReleases(lifetime.Release, release) <= 0 {
+			continue
+		}
+
+		if replacement == "" || CompareReleases(lifetime.Release, replacement) > 0 {
+			replacement = lifetime.Release
+		}
+
+		if lifetimeMajor, _, _ := strings.Cut(lifetime.Release, "."); lifetimeMajor == major {
+			if replacementSameMajor == "" || CompareReleases(lifetime.Release, replacementSameMajor) > 0 {
+				replacementSameMajor = lifetime.Release
+			}
+		}
+	}
+
+	if replacementSameMajor != "" {
+		return endOfLife, replacementSameMajor
+	}
+
+	return endOfLife, replacement
+}
+
+func parseReleaseLifetimes(data []byte) (map[string][]releaseLifetime, error) {
+	var lifetimes map[string][]releaseLifetime
+	if err := json.Unmarshal(data, &lifetimes); err != nil {
+		return nil, err
+	}
+
+	for distro, distroLifetimes := range lifetimes {
+		if _, supportedDistro := supportedDistros[distro]; !supportedDistro {
+			return nil, fmt.Errorf("distribution %s is unsupported", distro)
+		}
+
+		for i, lifetime := range distroLifetimes {
+			if lifetime.EndOfLife == "" {
+				continue
+			}
+
+			endOfLife, err := time.Parse(time.DateOnly, lifetime.EndOfLife)
+			if err != nil {
+				return nil, fmt.Errorf("invalid end of life for %s %s: %w", distro, lifetime.Release, err)
+			}
+
+			distroLifetimes[i].endOfLife = endOfLife
+		}
+	}
+
+	return lifetimes, nil
+}
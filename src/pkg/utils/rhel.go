@@ -67,3 +67,33 @@ func parseReleaseRHEL(release string) (string, error) {
 
 	return release, nil
 }
+
+// parseReleaseEnterpriseLinux parses the releases of rebuilds of Red Hat
+// Enterprise Linux, which have images for both major releases, like '9', and
+// minor releases, like '9.4'.
+func parseReleaseEnterpriseLinux(release string) (string, error) {
+	const hint = "The release must be in the '<major>' or '<major>.<minor>' format."
+
+	releaseParts := strings.Split(release, ".")
+	if len(releaseParts) > 2 {
+		return "", &ParseReleaseError{hint}
+	}
+
+	for _, releasePart := range releaseParts {
+		releasePartN, err := strconv.Atoi(releasePart)
+		if err != nil {
+			logrus.Debugf("Parsing release %s as an integer failed: %s", releasePart, err)
+			return "", &ParseReleaseError{hint}
+		}
+
+		if releasePartN < 0 {
+			return "", &ParseReleaseError{hint}
+		}
+	}
+
+	if releaseMajor, _ := strconv.Atoi(releaseParts[0]); releaseMajor == 0 {
+		return "", &ParseReleaseError{"The major release must be a positive integer."}
+	}
+
+	return release, nil
+}
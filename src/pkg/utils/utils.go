@@ -24,6 +24,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"syscall"
@@ -40,6 +41,7 @@ import (
 
 type GetDefaultReleaseFunc func() (string, error)
 type GetFullyQualifiedImageFunc func(string, string) string
+type GetImageTagFunc func(string) string
 type GetP11KitClientPathsFunc func() []string
 type ParseReleaseFunc func(string) (string, error)
 
@@ -49,6 +51,7 @@ type Distro struct {
 	ReleaseRequired        bool
 	GetDefaultRelease      GetDefaultReleaseFunc
 	GetFullyQualifiedImage GetFullyQualifiedImageFunc
+	GetImageTag            GetImageTagFunc
 	GetP11KitClientPaths   GetP11KitClientPathsFunc
 	ParseRelease           ParseReleaseFunc
 }
@@ -75,10 +78,28 @@ const (
 )
 
 var (
+	// The defaults for the host without any user-defined distributions, so
+	// that they can be restored when the configuration is set up again
+	builtInDefaults struct {
+		containerNamePrefix string
+		distro              string
+		release             string
+	}
+
 	containerNamePrefixDefault string
 
 	distroDefault string
 
+	// HomeEnvironmentVariables are preserved environment variables that point
+	// inside the user's home directory
+	HomeEnvironmentVariables = []string{
+		"HISTFILE",
+		"XDG_CACHE_HOME",
+		"XDG_CONFIG_HOME",
+		"XDG_DATA_HOME",
+		"XDG_STATE_HOME",
+	}
+
 	preservedEnvironmentVariables = []string{
 		"COLORTERM",
 		"CONTAINERS_STORAGE_CONF",
@@ -125,26 +146,62 @@ var (
 		"XTERM_VERSION",
 	}
 
+	// readOSRelease reads the os-release(5) files of the host, and can be
+	// replaced in tests.
+	readOSRelease = osrelease.Read
+
 	releaseDefault string
 
 	runtimeDirectories map[string]string
 
 	supportedDistros = map[string]Distro{
+		"almalinux": {
+			"almalinux-toolbox",
+			"almalinux-toolbox",
+			true,
+			getDefaultReleaseAlmaLinux,
+			getFullyQualifiedImageAlmaLinux,
+			nil,
+			getP11KitClientPathsAlmaLinux,
+			parseReleaseAlmaLinux,
+		},
 		"arch": {
 			"arch-toolbox",
 			"arch-toolbox",
 			false,
 			getDefaultReleaseArch,
 			getFullyQualifiedImageArch,
+			nil,
 			getP11KitClientPathsArch,
 			parseReleaseArch,
 		},
+		"centos": {
+			"centos-toolbox",
+			"centos-toolbox",
+			true,
+			getDefaultReleaseCentOS,
+			getFullyQualifiedImageCentOS,
+			getImageTagCentOS,
+			getP11KitClientPathsCentOS,
+			parseReleaseCentOS,
+		},
+		"debian": {
+			"debian-toolbox",
+			"debian-toolbox",
+			true,
+			getDefaultReleaseDebian,
+			getFullyQualifiedImageDebian,
+			nil,
+			getP11KitClientPathsDebian,
+			parseReleaseDebian,
+		},
 		"fedora": {
 			"fedora-toolbox",
 			"fedora-toolbox",
 			true,
 			getDefaultReleaseFedora,
 			getFullyQualifiedImageFedora,
+			nil,
 			getP11KitClientPathsFedora,
 			parseReleaseFedora,
 		},
@@ -154,15 +211,27 @@ var (
 			true,
 			getDefaultReleaseRHEL,
 			getFullyQualifiedImageRHEL,
+			nil,
 			getP11KitClientPathsRHEL,
 			parseReleaseRHEL,
 		},
+		"rocky": {
+			"rocky-toolbox",
+			"rockylinux-toolbox",
+			true,
+			getDefaultReleaseRocky,
+			getFullyQualifiedImageRocky,
+			nil,
+			getP11KitClientPathsRocky,
+			parseReleaseRocky,
+		},
 		"ubuntu": {
 			"ubuntu-toolbox",
 			"ubuntu-toolbox",
 			true,
 			getDefaultReleaseUbuntu,
 			getFullyQualifiedImageUbuntu,
+			nil,
 			getP11KitClientPathsUbuntu,
 			parseReleaseUbuntu,
 		},
@@ -204,6 +273,10 @@ func init() {
 		}
 	}
 
+	builtInDefaults.containerNamePrefix = containerNamePrefixDefault
+	builtInDefaults.distro = distroDefault
+	builtInDefaults.release = releaseDefault
+
 	ContainerNameDefault = containerNamePrefixDefault + "-" + releaseDefault
 }
 
@@ -266,6 +339,21 @@ func Flock(path string, how int) (*os.File, error) {
 
 func ForwardToHost() (int, error) {
 	envOptions := GetEnvOptionsForPreservedVariables()
+
+	// Toolbx containers with a separate home directory or XDG directories
+	// remember the home directory from the host in TOOLBX_HOST_HOME, and the
+	// variables pointing inside the container's own directories must not
+	// leak out to the host
+	if hostHome, ok := os.LookupEnv("TOOLBX_HOST_HOME"); ok {
+		envOptions = slices.DeleteFunc(envOptions, func(envOption string) bool {
+			envOption = strings.TrimPrefix(envOption, "--env=")
+			variable, _, _ := strings.Cut(envOption, "=")
+			return variable == "HOME" || slices.Contains(HomeEnvironmentVariables, variable)
+		})
+
+		envOptions = append(envOptions, "--env=HOME="+hostHome)
+	}
+
 	toolboxPath := os.Getenv("TOOLBOX_PATH")
 	commandLineArgs := os.Args[1:]
 
@@ -318,8 +406,8 @@ func getContainerNamePrefixForImage(image string) (string, error) {
 		return "", &ImageError{image, ErrImageWithoutBasename}
 	}
 
-	for _, distroObj := range supportedDistros {
-		if distroObj.ImageBasename != basename {
+	for distro, distroObj := range supportedDistros {
+		if !isImageBasenameForDistro(distro, basename) {
 			continue
 		}
 
@@ -334,13 +422,17 @@ func getDefaultImageForDistro(distro, release string) string {
 		panic("distro not specified")
 	}
 
-	distroObj, supportedDistro := supportedDistros[distro]
-	if !supportedDistro {
+	if _, supportedDistro := supportedDistros[distro]; !supportedDistro {
 		panicMsg := fmt.Sprintf("failed to find %s in the list of supported distributions", distro)
 		panic(panicMsg)
 	}
 
-	image := distroObj.ImageBasename + ":" + release
+	tag := release
+	if getImageTagImpl := supportedDistros[distro].GetImageTag; getImageTagImpl != nil {
+		tag = getImageTagImpl(release)
+	}
+
+	image := getImageBasenameForDistro(distro) + ":" + tag
 	return image
 }
 
@@ -363,12 +455,46 @@ func getDefaultReleaseForDistro(distro string) (string, error) {
 	return release, nil
 }
 
+// GetDistroAndReleaseForImage returns the operating system distribution and
+// release of an image, if its basename matches one of the supported
+// distributions.  Otherwise, empty strings are returned.
+func GetDistroAndReleaseForImage(image string) (string, string) {
+	basename := ImageReferenceGetBasename(image)
+	if basename == "" {
+		return "", ""
+	}
+
+	for distro := range supportedDistros {
+		if !isImageBasenameForDistro(distro, basename) {
+			continue
+		}
+
+		release := ImageReferenceGetTag(image)
+		if release == "" {
+			return distro, ""
+		}
+
+		if releaseParsed, err := parseRelease(distro, release); err == nil {
+			release = releaseParsed
+		}
+
+		return distro, release
+	}
+
+	return "", ""
+}
+
 func GetEnvOptionsForPreservedVariables() []string {
 	logrus.Debug("Creating list of environment variables to forward")
 
 	var envOptions []string
 
-	for _, variable := range preservedEnvironmentVariables {
+	variables := getPreservedEnvironmentVariables(preservedEnvironmentVariables,
+		viper.GetStringSlice("environment.preserve"),
+		viper.GetStringSlice("environment.discard"),
+		os.Environ())
+
+	for _, variable := range variables {
 		value, found := os.LookupEnv(variable)
 		if !found {
 			logrus.Debugf("%s is unset", variable)
@@ -382,6 +508,65 @@ func GetEnvOptionsForPreservedVariables() []string {
 	return envOptions
 }
 
+// getPreservedEnvironmentVariables returns the names of the environment
+// variables that should be forwarded between the host and the container. The
+// defaults are extended by the names or glob patterns in preserve, which are
+// matched against environ, and then the ones matching discard are dropped.
+func getPreservedEnvironmentVariables(defaults, preserve, discard, environ []string) []string {
+	var variables []string
+	seen := make(map[string]struct{})
+
+	isDiscarded := func(variable string) bool {
+		for _, pattern := range discard {
+			if matched, err := path.Match(pattern, variable); err == nil && matched {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	addVariable := func(variable string) {
+		if _, ok := seen[variable]; ok {
+			return
+		}
+
+		seen[variable] = struct{}{}
+
+		if isDiscarded(variable) {
+			logrus.Debugf("%s is discarded by the configuration", variable)
+			return
+		}
+
+		variables = append(variables, variable)
+	}
+
+	for _, variable := range defaults {
+		addVariable(variable)
+	}
+
+	for _, pattern := range preserve {
+		if !strings.ContainsAny(pattern, "*?[") {
+			addVariable(pattern)
+			continue
+		}
+
+		if _, err := path.Match(pattern, ""); err != nil {
+			logrus.Debugf("Ignoring invalid pattern %s in the configuration: %s", pattern, err)
+			continue
+		}
+
+		for _, env := range environ {
+			variable, _, _ := strings.Cut(env, "=")
+			if matched, _ := path.Match(pattern, variable); matched {
+				addVariable(variable)
+			}
+		}
+	}
+
+	return variables
+}
+
 func GetFullyQualifiedImageFromDistros(image, release string) (string, error) {
 	logrus.Debugf("Resolving fully qualified name for image %s from known registries", image)
 
@@ -393,9 +578,12 @@ func GetFullyQualifiedImageFromDistros(image, release string) (string, error) {
 		panic("release not specified")
 	}
 
+	// Some tags, like 'stream9' for CentOS Stream, aren't the release itself
 	if tag := ImageReferenceGetTag(image); tag != "" && release != tag {
-		panicMsg := fmt.Sprintf("image %s does not match release %s", image, release)
-		panic(panicMsg)
+		if _, releaseForImage := GetDistroAndReleaseForImage(image); release != releaseForImage {
+			panicMsg := fmt.Sprintf("image %s does not match release %s", image, release)
+			panic(panicMsg)
+		}
 	}
 
 	if ImageReferenceHasDomain(image) {
@@ -407,14 +595,19 @@ func GetFullyQualifiedImageFromDistros(image, release string) (string, error) {
 		return "", fmt.Errorf("failed to get the basename of image %s", image)
 	}
 
-	for _, distroObj := range supportedDistros {
-		if distroObj.ImageBasename != basename {
+	for distro, distroObj := range supportedDistros {
+		if !isImageBasenameForDistro(distro, basename) {
 			continue
 		}
 
 		getFullyQualifiedImageImpl := distroObj.GetFullyQualifiedImage
 		imageFull := getFullyQualifiedImageImpl(image, release)
 
+		if registry := getRegistryForDistro(distro); registry != "" {
+			i := strings.IndexRune(imageFull, '/')
+			imageFull = registry + imageFull[i:]
+		}
+
 		logrus.Debugf("Resolved image %s to %s", image, imageFull)
 
 		return imageFull, nil
@@ -446,7 +639,7 @@ func GetGroupForSudo() (string, error) {
 // Examples:
 // - host is Fedora, returned string is 'fedora'
 func getHostID() (string, error) {
-	osRelease, err := osrelease.Read()
+	osRelease, err := readOSRelease()
 	if err != nil {
 		return "", err
 	}
@@ -459,7 +652,7 @@ func getHostID() (string, error) {
 // Examples:
 // - host is Fedora 32, returned string is '32'
 func getHostVersionID() (string, error) {
-	osRelease, err := osrelease.Read()
+	osRelease, err := readOSRelease()
 	if err != nil {
 		return "", err
 	}
@@ -467,6 +660,110 @@ func getHostVersionID() (string, error) {
 	return osRelease["VERSION_ID"], nil
 }
 
+// getUserConfigDir returns the user's configuration directory. For the host,
+// inside Toolbx containers with a separate home directory or XDG directories,
+// that's the one remembered from the host in TOOLBX_HOST_XDG_CONFIG_HOME or
+// TOOLBX_HOST_HOME, because XDG_CONFIG_HOME and HOME point inside the
+// container's own directories.
+func getUserConfigDir(host bool) (string, error) {
+	if host {
+		if hostConfigHome := os.Getenv("TOOLBX_HOST_XDG_CONFIG_HOME"); hostConfigHome != "" {
+			return hostConfigHome, nil
+		}
+
+		if hostHome := os.Getenv("TOOLBX_HOST_HOME"); hostHome != "" {
+			userConfigDir := filepath.Join(hostHome, ".config")
+			return userConfigDir, nil
+		}
+	}
+
+	userConfigDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return userConfigDir, nil
+}
+
+// isImageBasenameForDistro returns whether images with the basename belong to
+// the distribution. Both the built-in basename and the one from the
+// configuration are recognized, so that images and containers from before
+// the configuration was changed are still known.
+func isImageBasenameForDistro(distro, basename string) bool {
+	distroObj, supportedDistro := supportedDistros[distro]
+	if !supportedDistro {
+		panicMsg := fmt.Sprintf("failed to find %s in the list of supported distributions", distro)
+		panic(panicMsg)
+	}
+
+	if basename == distroObj.ImageBasename {
+		return true
+	}
+
+	return basename == getImageBasenameForDistro(distro)
+}
+
+// getImageBasenameForDistro returns the basename of the default images for
+// the distribution, which can be changed in the configuration to use rebuilt
+// images.
+func getImageBasenameForDistro(distro string) string {
+	if distro == "" {
+		panic("distro not specified")
+	}
+
+	distroObj, supportedDistro := supportedDistros[distro]
+	if !supportedDistro {
+		panicMsg := fmt.Sprintf("failed to find %s in the list of supported distributions", distro)
+		panic(panicMsg)
+	}
+
+	if key := "distro." + distro + ".image"; viper.IsSet(key) {
+		basename := viper.GetString(key)
+		return basename
+	}
+
+	return distroObj.ImageBasename
+}
+
+// GetCheckUpdates returns true if 'toolbox enter' should tell when a newer
+// image is available for the Toolbx container, as set in the configuration.
+func GetCheckUpdates() bool {
+	checkUpdates := viper.GetBool("general.check-updates")
+	return checkUpdates
+}
+
+// GetHook returns the command that should be run on the host for the hook
+// with the given name, as set in the configuration.
+func GetHook(name string) string {
+	hook := viper.GetString("hooks." + name)
+	return hook
+}
+
+// GetIdleTimeout returns the number of minutes after which a Toolbx container
+// without any active sessions should stop itself, as set in the configuration.
+// Zero means that the container should never stop itself.
+func GetIdleTimeout() (uint, error) {
+	if !viper.IsSet("general.idle-timeout") {
+		return 0, nil
+	}
+
+	idleTimeoutString := viper.GetString("general.idle-timeout")
+	idleTimeout, err := strconv.ParseUint(idleTimeoutString, 10, 0)
+	if err != nil {
+		logrus.Debugf("Parsing idle-timeout %s failed: %s", idleTimeoutString, err)
+		return 0, fmt.Errorf("invalid idle-timeout %s in configuration", idleTimeoutString)
+	}
+
+	return uint(idleTimeout), nil
+}
+
+// GetIsolatedXDGDirs returns true if newly created Toolbx containers should
+// use separate XDG directories, as set in the configuration.
+func GetIsolatedXDGDirs() bool {
+	isolatedXDGDirs := viper.GetBool("general.isolated-xdg-dirs")
+	return isolatedXDGDirs
+}
+
 func GetInitializedStamp(entryPointPID int, targetUser *user.User) (string, error) {
 	toolbxRuntimeDirectory, err := GetRuntimeDirectory(targetUser)
 	if err != nil {
@@ -498,6 +795,20 @@ func GetP11KitServerSocketLock(targetUser *user.User) (string, error) {
 	return p11KitServerSocketLock, nil
 }
 
+// getRegistryForDistro returns the registry that replaces the one where the
+// images for the distribution are published, as set in the configuration, or
+// an empty string if it's not changed. Distributions defined in the
+// configuration already use their registry.
+func getRegistryForDistro(distro string) string {
+	if _, userDistro := userDistros[distro]; userDistro {
+		return ""
+	}
+
+	registry := viper.GetString("distro." + distro + ".registry")
+	registry = strings.TrimSuffix(registry, "/")
+	return registry
+}
+
 func GetRuntimeDirectory(targetUser *user.User) (string, error) {
 	if runtimeDirectories == nil {
 		runtimeDirectories = make(map[string]string)
@@ -543,6 +854,20 @@ func GetRuntimeDirectory(targetUser *user.User) (string, error) {
 	return toolboxRuntimeDirectory, nil
 }
 
+// GetSessionsDirectory returns the directory where processes on the host that
+// are running commands inside the Toolbx container with the given entry point
+// hold a lock on a file for as long as they are running.
+func GetSessionsDirectory(entryPointPID int, targetUser *user.User) (string, error) {
+	toolbxRuntimeDirectory, err := GetRuntimeDirectory(targetUser)
+	if err != nil {
+		return "", err
+	}
+
+	sessionsDirectoryBase := fmt.Sprintf("container-sessions-%d", entryPointPID)
+	sessionsDirectory := filepath.Join(toolbxRuntimeDirectory, sessionsDirectoryBase)
+	return sessionsDirectory, nil
+}
+
 // GetSupportedDistros returns a list of supported distributions
 func GetSupportedDistros() []string {
 	var distros []string
@@ -553,6 +878,17 @@ func GetSupportedDistros() []string {
 	return distros
 }
 
+// GetVolumes returns the extra volumes that should be added to newly created
+// Toolbx containers, as set in the configuration.
+func GetVolumes() []string {
+	if !viper.IsSet("general.volumes") {
+		return nil
+	}
+
+	volumes := viper.GetStringSlice("general.volumes")
+	return volumes
+}
+
 // HumanDuration accepts a Unix time value and converts it into a human readable
 // string.
 //
@@ -631,6 +967,19 @@ func ImageReferenceHasDomain(image string) bool {
 	return true
 }
 
+// ImageReferenceIsArchive checks if 'image' refers to an image in an archive or
+// a directory, instead of a registry or the local storage, using one of the
+// docker-archive, oci-archive or oci transports.
+func ImageReferenceIsArchive(image string) bool {
+	for _, transport := range []string{"docker-archive:", "oci-archive:", "oci:"} {
+		if strings.HasPrefix(image, transport) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func IsP11KitClientPresent() (bool, error) {
 	var p11KitClientPaths []string
 	var supportedDistro bool
@@ -664,14 +1013,23 @@ func IsP11KitClientPresent() (bool, error) {
 	return false, err
 }
 
-func SetUpConfiguration() error {
+func SetUpConfiguration(forwardToHost bool) error {
 	logrus.Debug("Setting up configuration")
 
 	configFiles := []string{
 		"/etc/containers/toolbox.conf",
 	}
 
-	userConfigDir, err := os.UserConfigDir()
+	// Commands forwarded to the host need to be configured like the host,
+	// but those running inside the container, like 'toolbox init-container',
+	// are configured like the container
+	if forwardToHost && IsInsideToolboxContainer() {
+		configFiles = []string{
+			"/run/host/etc/containers/toolbox.conf",
+		}
+	}
+
+	userConfigDir, err := getUserConfigDir(forwardToHost && IsInsideToolboxContainer())
 	if err != nil {
 		logrus.Debugf("Setting up configuration: failed to get the user config directory: %s", err)
 		return errors.New("failed to get the user config directory")
@@ -704,6 +1062,11 @@ func SetUpConfiguration() error {
 		}
 	}
 
+	if err := setUpDistros(); err != nil {
+		logrus.Debugf("Setting up configuration: %s", err)
+		return err
+	}
+
 	container, _, _, err := ResolveContainerAndImageNames("", "", "", "")
 	if err != nil {
 		logrus.Debugf("Setting up configuration: failed to resolve container name: %s", err)
@@ -739,6 +1102,16 @@ func parseRelease(distro, release string) (string, error) {
 	return release, err
 }
 
+// ParseRelease is like parseRelease, but doesn't expect distro to be
+// supported.
+func ParseRelease(distro, release string) (string, error) {
+	if _, supportedDistro := supportedDistros[distro]; !supportedDistro {
+		return "", &DistroError{distro, ErrDistroUnsupported}
+	}
+
+	return parseRelease(distro, release)
+}
+
 // PathExists wraps around os.Stat providing a nice interface for checking an existence of a path.
 func PathExists(path string) bool {
 	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
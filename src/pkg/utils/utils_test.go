@@ -20,7 +20,10 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/acobaugh/osrelease"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -76,6 +79,590 @@ func TestImageReferenceCanBeID(t *testing.T) {
 	}
 }
 
+func TestImageReferenceIsArchive(t *testing.T) {
+	testCases := []struct {
+		name string
+		ref  string
+		ok   bool
+	}{
+		{
+			name: "Docker archive",
+			ref:  "docker-archive:/tmp/fedora-toolbox.tar",
+			ok:   true,
+		},
+		{
+			name: "OCI archive",
+			ref:  "oci-archive:/tmp/fedora-toolbox.tar",
+			ok:   true,
+		},
+		{
+			name: "OCI directory with a tag",
+			ref:  "oci:/tmp/fedora-toolbox:40",
+			ok:   true,
+		},
+		{
+			name: "Registry",
+			ref:  "registry.fedoraproject.org/fedora-toolbox:40",
+			ok:   false,
+		},
+		{
+			name: "Registry with the docker transport",
+			ref:  "docker://registry.fedoraproject.org/fedora-toolbox:40",
+			ok:   false,
+		},
+		{
+			name: "Short name",
+			ref:  "fedora-toolbox:40",
+			ok:   false,
+		},
+		{
+			name: "ID",
+			ref:  "8215cb84fa58",
+			ok:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ok := ImageReferenceIsArchive(tc.ref)
+			assert.Equal(t, tc.ok, ok)
+		})
+	}
+}
+
+func TestGetFullyQualifiedImageFromDistros(t *testing.T) {
+	testCases := []struct {
+		name      string
+		config    map[string]string
+		image     string
+		release   string
+		imageFull string
+	}{
+		{
+			name:      "Fedora",
+			image:     "fedora-toolbox:40",
+			release:   "40",
+			imageFull: "registry.fedoraproject.org/fedora-toolbox:40",
+		},
+		{
+			name:      "AlmaLinux",
+			image:     "almalinux-toolbox:9.4",
+			release:   "9.4",
+			imageFull: "quay.io/toolbx-images/almalinux-toolbox:9.4",
+		},
+		{
+			name:      "CentOS Stream",
+			image:     "centos-toolbox:stream9",
+			release:   "9",
+			imageFull: "quay.io/toolbx-images/centos-toolbox:stream9",
+		},
+		{
+			name:      "Debian",
+			image:     "debian-toolbox:12",
+			release:   "12",
+			imageFull: "quay.io/toolbx-images/debian-toolbox:12",
+		},
+		{
+			name:      "Rocky Linux",
+			image:     "rockylinux-toolbox:9",
+			release:   "9",
+			imageFull: "quay.io/toolbx-images/rockylinux-toolbox:9",
+		},
+		{
+			name: "Fedora from a mirror",
+			config: map[string]string{
+				"distro.fedora.registry": "mirror.example.com",
+			},
+			image:     "fedora-toolbox:40",
+			release:   "40",
+			imageFull: "mirror.example.com/fedora-toolbox:40",
+		},
+		{
+			name: "Fedora rebuilt",
+			config: map[string]string{
+				"distro.fedora.image":    "corp-fedora-toolbox",
+				"distro.fedora.registry": "registry.example.com/toolbx/",
+			},
+			image:     "corp-fedora-toolbox:40",
+			release:   "40",
+			imageFull: "registry.example.com/toolbx/corp-fedora-toolbox:40",
+		},
+		{
+			name: "Fedora with the built-in image, when rebuilt",
+			config: map[string]string{
+				"distro.fedora.image":    "corp-fedora-toolbox",
+				"distro.fedora.registry": "registry.example.com/toolbx/",
+			},
+			image:     "fedora-toolbox:40",
+			release:   "40",
+			imageFull: "registry.example.com/toolbx/fedora-toolbox:40",
+		},
+		{
+			name: "RHEL from a mirror",
+			config: map[string]string{
+				"distro.rhel.registry": "mirror.example.com:5000",
+			},
+			image:     "toolbox:9.4",
+			release:   "9.4",
+			imageFull: "mirror.example.com:5000/ubi9/toolbox:9.4",
+		},
+		{
+			name: "Ubuntu with a mirror for Fedora",
+			config: map[string]string{
+				"distro.fedora.registry": "mirror.example.com",
+			},
+			image:     "ubuntu-toolbox:24.04",
+			release:   "24.04",
+			imageFull: "quay.io/toolbx/ubuntu-toolbox:24.04",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(viper.Reset)
+
+			for key, value := range tc.config {
+				viper.Set(key, value)
+			}
+
+			imageFull, err := GetFullyQualifiedImageFromDistros(tc.image, tc.release)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.imageFull, imageFull)
+		})
+	}
+}
+
+func TestGetDistroAndReleaseForImage(t *testing.T) {
+	testCases := []struct {
+		image   string
+		distro  string
+		release string
+	}{
+		{
+			image:   "quay.io/toolbx-images/almalinux-toolbox:9.4",
+			distro:  "almalinux",
+			release: "9.4",
+		},
+		{
+			image:   "quay.io/toolbx-images/centos-toolbox:stream9",
+			distro:  "centos",
+			release: "9",
+		},
+		{
+			image:   "quay.io/toolbx-images/rockylinux-toolbox:8.10",
+			distro:  "rocky",
+			release: "8.10",
+		},
+		{
+			image:   "registry.access.redhat.com/ubi9/toolbox:9.4",
+			distro:  "rhel",
+			release: "9.4",
+		},
+		{
+			image: "localhost/custom:1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.image, func(t *testing.T) {
+			distro, release := GetDistroAndReleaseForImage(tc.image)
+			assert.Equal(t, tc.distro, distro)
+			assert.Equal(t, tc.release, release)
+		})
+	}
+}
+
+func TestImageBasenameForRebuiltImages(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	viper.Set("distro.fedora.image", "corp-fedora-toolbox")
+
+	image := getDefaultImageForDistro("fedora", "40")
+	assert.Equal(t, "corp-fedora-toolbox:40", image)
+
+	for _, image := range []string{
+		"registry.fedoraproject.org/fedora-toolbox:40",
+		"registry.example.com/corp-fedora-toolbox:40",
+	} {
+		t.Run(image, func(t *testing.T) {
+			distro, release := GetDistroAndReleaseForImage(image)
+			assert.Equal(t, "fedora", distro)
+			assert.Equal(t, "40", release)
+
+			prefix, err := getContainerNamePrefixForImage(image)
+			assert.NoError(t, err)
+			assert.Equal(t, "fedora-toolbox", prefix)
+		})
+	}
+}
+
+// The IDs in os-release(5) are used to find the distribution of the host, so
+// they must be the names of the distributions.
+func TestSupportedDistrosForHosts(t *testing.T) {
+	testCases := []struct {
+		name      string
+		osRelease string
+		distro    string
+		release   string
+		image     string
+	}{
+		{
+			name: "AlmaLinux 9.4",
+			osRelease: `NAME="AlmaLinux"
+VERSION="9.4 (Seafoam Ocelot)"
+ID="almalinux"
+ID_LIKE="rhel centos fedora"
+VERSION_ID="9.4"`,
+			distro:  "almalinux",
+			release: "9.4",
+			image:   "almalinux-toolbox:9.4",
+		},
+		{
+			name: "Arch Linux",
+			osRelease: `NAME="Arch Linux"
+PRETTY_NAME="Arch Linux"
+ID=arch
+BUILD_ID=rolling`,
+			distro:  "arch",
+			release: "latest",
+			image:   "arch-toolbox:latest",
+		},
+		{
+			name: "CentOS Stream 9",
+			osRelease: `NAME="CentOS Stream"
+VERSION="9"
+ID="centos"
+ID_LIKE="rhel fedora"
+VERSION_ID="9"`,
+			distro:  "centos",
+			release: "9",
+			image:   "centos-toolbox:stream9",
+		},
+		{
+			name: "Debian 12",
+			osRelease: `PRETTY_NAME="Debian GNU/Linux 12 (bookworm)"
+NAME="Debian GNU/Linux"
+VERSION_ID="12"
+VERSION_CODENAME=bookworm
+ID=debian`,
+			distro:  "debian",
+			release: "12",
+			image:   "debian-toolbox:12",
+		},
+		{
+			name: "Debian testing",
+			osRelease: `PRETTY_NAME="Debian GNU/Linux trixie/sid"
+NAME="Debian GNU/Linux"
+VERSION_CODENAME=trixie
+ID=debian`,
+			distro:  "debian",
+			release: "testing",
+			image:   "debian-toolbox:testing",
+		},
+		{
+			name: "Fedora 40",
+			osRelease: `NAME="Fedora Linux"
+VERSION="40 (Workstation Edition)"
+ID=fedora
+VERSION_ID=40`,
+			distro:  "fedora",
+			release: "40",
+			image:   "fedora-toolbox:40",
+		},
+		{
+			name: "RHEL 9.4",
+			osRelease: `NAME="Red Hat Enterprise Linux"
+VERSION="9.4 (Plow)"
+ID="rhel"
+ID_LIKE="fedora"
+VERSION_ID="9.4"`,
+			distro:  "rhel",
+			release: "9.4",
+			image:   "toolbox:9.4",
+		},
+		{
+			name: "Rocky Linux 9.4",
+			osRelease: `NAME="Rocky Linux"
+VERSION="9.4 (Blue Onyx)"
+ID="rocky"
+ID_LIKE="rhel centos fedora"
+VERSION_ID="9.4"`,
+			distro:  "rocky",
+			release: "9.4",
+			image:   "rockylinux-toolbox:9.4",
+		},
+		{
+			name: "Ubuntu 24.04",
+			osRelease: `PRETTY_NAME="Ubuntu 24.04 LTS"
+NAME="Ubuntu"
+VERSION_ID="24.04"
+VERSION_CODENAME=noble
+ID=ubuntu`,
+			distro:  "ubuntu",
+			release: "24.04",
+			image:   "ubuntu-toolbox:24.04",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			debianAptDirOld := debianAptDir
+			readOSReleaseOld := readOSRelease
+			t.Cleanup(func() {
+				debianAptDir = debianAptDirOld
+				readOSRelease = readOSReleaseOld
+			})
+
+			debianAptDir = t.TempDir()
+			readOSRelease = func() (map[string]string, error) {
+				return osrelease.ReadString(tc.osRelease)
+			}
+
+			distro, err := getHostID()
+			require.NoError(t, err)
+			assert.Equal(t, tc.distro, distro)
+			require.Contains(t, supportedDistros, distro)
+
+			release, err := getDefaultReleaseForDistro(distro)
+			require.NoError(t, err)
+			assert.Equal(t, tc.release, release)
+
+			image := getDefaultImageForDistro(distro, release)
+			assert.Equal(t, tc.image, image)
+
+			distroForImage, releaseForImage := GetDistroAndReleaseForImage(image)
+			assert.Equal(t, tc.distro, distroForImage)
+			assert.Equal(t, tc.release, releaseForImage)
+		})
+	}
+}
+
+func TestGetUserConfigDir(t *testing.T) {
+	testCases := []struct {
+		name               string
+		host               bool
+		hostXDGConfigHome  string
+		hostHome           string
+		xdgConfigHome      string
+		expectedConfigPath string
+	}{
+		{
+			name:               "container",
+			hostHome:           "/home/user",
+			xdgConfigHome:      "/home/user/.local/share/toolbox/homes/dev/.config",
+			expectedConfigPath: "/home/user/.local/share/toolbox/homes/dev/.config",
+		},
+		{
+			name:               "host, isolated home",
+			host:               true,
+			hostHome:           "/home/user",
+			xdgConfigHome:      "/home/user/.local/share/toolbox/homes/dev/.config",
+			expectedConfigPath: "/home/user/.config",
+		},
+		{
+			name:               "host, isolated XDG directories",
+			host:               true,
+			hostXDGConfigHome:  "/home/user/.my-config",
+			hostHome:           "/home/user",
+			xdgConfigHome:      "/home/user/.local/share/toolbox/xdg/dev/config",
+			expectedConfigPath: "/home/user/.my-config",
+		},
+		{
+			name:               "host, shared home",
+			host:               true,
+			xdgConfigHome:      "/home/user/.config",
+			expectedConfigPath: "/home/user/.config",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("TOOLBX_HOST_XDG_CONFIG_HOME", tc.hostXDGConfigHome)
+			t.Setenv("TOOLBX_HOST_HOME", tc.hostHome)
+			t.Setenv("XDG_CONFIG_HOME", tc.xdgConfigHome)
+
+			userConfigDir, err := getUserConfigDir(tc.host)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedConfigPath, userConfigDir)
+		})
+	}
+}
+
+func TestGetPreservedEnvironmentVariables(t *testing.T) {
+	defaults := []string{"HOME", "LANG", "TERM"}
+	environ := []string{
+		"HOME=/home/user",
+		"SSH_AGENT_PID=1234",
+		"SSH_AUTH_SOCK=/run/user/1000/ssh-agent.socket",
+		"TERM=xterm-256color",
+		"https_proxy=http://proxy.example.com:3128",
+	}
+
+	testCases := []struct {
+		name     string
+		preserve []string
+		discard  []string
+		expect   []string
+	}{
+		{
+			name:   "defaults",
+			expect: []string{"HOME", "LANG", "TERM"},
+		},
+		{
+			name:     "extra names",
+			preserve: []string{"EDITOR", "https_proxy"},
+			expect:   []string{"HOME", "LANG", "TERM", "EDITOR", "https_proxy"},
+		},
+		{
+			name:     "extra glob",
+			preserve: []string{"SSH_*"},
+			expect:   []string{"HOME", "LANG", "TERM", "SSH_AGENT_PID", "SSH_AUTH_SOCK"},
+		},
+		{
+			name:     "extra name already in defaults",
+			preserve: []string{"TERM"},
+			expect:   []string{"HOME", "LANG", "TERM"},
+		},
+		{
+			name:    "discard name",
+			discard: []string{"LANG"},
+			expect:  []string{"HOME", "TERM"},
+		},
+		{
+			name:     "discard glob",
+			preserve: []string{"SSH_*"},
+			discard:  []string{"SSH_AGENT_*"},
+			expect:   []string{"HOME", "LANG", "TERM", "SSH_AUTH_SOCK"},
+		},
+		{
+			name:     "invalid glob",
+			preserve: []string{"SSH_["},
+			expect:   []string{"HOME", "LANG", "TERM"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			variables := getPreservedEnvironmentVariables(defaults, tc.preserve, tc.discard, environ)
+			assert.Equal(t, tc.expect, variables)
+		})
+	}
+}
+
+func TestGetDefaultReleaseDebian(t *testing.T) {
+	// Debian testing and unstable share the same os-release file
+	osReleaseTestingUnstable := `PRETTY_NAME="Debian GNU/Linux trixie/sid"
+NAME="Debian GNU/Linux"
+VERSION_CODENAME=trixie
+ID=debian
+HOME_URL="https://www.debian.org/"
+SUPPORT_URL="https://www.debian.org/support"
+BUG_REPORT_URL="https://bugs.debian.org/"`
+
+	testCases := []struct {
+		name        string
+		osRelease   string
+		sourcesList string
+		sources     string
+		release     string
+	}{
+		{
+			name: "Debian 12",
+			osRelease: `PRETTY_NAME="Debian GNU/Linux 12 (bookworm)"
+NAME="Debian GNU/Linux"
+VERSION_ID="12"
+VERSION="12 (bookworm)"
+VERSION_CODENAME=bookworm
+ID=debian
+HOME_URL="https://www.debian.org/"
+SUPPORT_URL="https://www.debian.org/support"
+BUG_REPORT_URL="https://bugs.debian.org/"`,
+			sourcesList: "deb http://deb.debian.org/debian sid main",
+			release:     "12",
+		},
+		{
+			name:      "Debian testing without APT sources",
+			osRelease: osReleaseTestingUnstable,
+			release:   "testing",
+		},
+		{
+			name:      "Debian testing",
+			osRelease: osReleaseTestingUnstable,
+			sourcesList: `# See sources.list(5)
+deb http://deb.debian.org/debian testing main
+deb http://deb.debian.org/debian-security testing-security main`,
+			release: "testing",
+		},
+		{
+			name:      "Debian testing by codename",
+			osRelease: osReleaseTestingUnstable,
+			sources: `Types: deb
+URIs: http://deb.debian.org/debian
+Suites: trixie trixie-updates
+Components: main
+Signed-By: /usr/share/keyrings/debian-archive-keyring.gpg`,
+			release: "testing",
+		},
+		{
+			name:      "Debian testing with unstable pinned",
+			osRelease: osReleaseTestingUnstable,
+			sourcesList: `deb http://deb.debian.org/debian testing main
+deb http://deb.debian.org/debian unstable main`,
+			release: "testing",
+		},
+		{
+			name:        "Debian unstable",
+			osRelease:   osReleaseTestingUnstable,
+			sourcesList: "deb [signed-by=/usr/share/keyrings/debian-archive-keyring.gpg] http://deb.debian.org/debian sid main",
+			release:     "unstable",
+		},
+		{
+			name:      "Debian unstable with deb822 sources",
+			osRelease: osReleaseTestingUnstable,
+			sources: `Types: deb deb-src
+URIs: http://deb.debian.org/debian
+Suites: unstable
+Components: main contrib`,
+			release: "unstable",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			debianAptDirOld := debianAptDir
+			readOSReleaseOld := readOSRelease
+			t.Cleanup(func() {
+				debianAptDir = debianAptDirOld
+				readOSRelease = readOSReleaseOld
+			})
+
+			debianAptDir = t.TempDir()
+			readOSRelease = func() (map[string]string, error) {
+				return osrelease.ReadString(tc.osRelease)
+			}
+
+			if tc.sourcesList != "" {
+				path := filepath.Join(debianAptDir, "sources.list")
+				err := os.WriteFile(path, []byte(tc.sourcesList), 0644)
+				require.NoError(t, err)
+			}
+
+			if tc.sources != "" {
+				dir := filepath.Join(debianAptDir, "sources.list.d")
+				err := os.MkdirAll(dir, 0755)
+				require.NoError(t, err)
+
+				path := filepath.Join(dir, "debian.sources")
+				err = os.WriteFile(path, []byte(tc.sources), 0644)
+				require.NoError(t, err)
+			}
+
+			release, err := getDefaultReleaseDebian()
+			require.NoError(t, err)
+			assert.Equal(t, tc.release, release)
+		})
+	}
+}
+
 func TestParseRelease(t *testing.T) {
 	testCases := []struct {
 		inputDistro  string
@@ -83,6 +670,56 @@ func TestParseRelease(t *testing.T) {
 		output       string
 		errMsg       string
 	}{
+		{
+			inputDistro:  "almalinux",
+			inputRelease: "9",
+			output:       "9",
+		},
+		{
+			inputDistro:  "almalinux",
+			inputRelease: "9.4",
+			output:       "9.4",
+		},
+		{
+			inputDistro:  "almalinux",
+			inputRelease: "10.0",
+			output:       "10.0",
+		},
+		{
+			inputDistro:  "almalinux",
+			inputRelease: "",
+			errMsg:       "The release must be in the '<major>' or '<major>.<minor>' format.",
+		},
+		{
+			inputDistro:  "almalinux",
+			inputRelease: "foo",
+			errMsg:       "The release must be in the '<major>' or '<major>.<minor>' format.",
+		},
+		{
+			inputDistro:  "almalinux",
+			inputRelease: "9.4.1",
+			errMsg:       "The release must be in the '<major>' or '<major>.<minor>' format.",
+		},
+		{
+			inputDistro:  "almalinux",
+			inputRelease: "9.",
+			errMsg:       "The release must be in the '<major>' or '<major>.<minor>' format.",
+		},
+		{
+			inputDistro:  "almalinux",
+			inputRelease: "9.-1",
+			errMsg:       "The release must be in the '<major>' or '<major>.<minor>' format.",
+		},
+		{
+			inputDistro:  "almalinux",
+			inputRelease: "0",
+			errMsg:       "The major release must be a positive integer.",
+		},
+		{
+			inputDistro:  "almalinux",
+			inputRelease: "0.1",
+			errMsg:       "The major release must be a positive integer.",
+		},
 		{
 			inputDistro:  "arch",
 			inputRelease: "",
@@ -103,6 +740,96 @@ func TestParseRelease(t *testing.T) {
 			inputRelease: "foo",
 			errMsg:       "The release must be 'latest'.",
 		},
+		{
+			inputDistro:  "centos",
+			inputRelease: "9",
+			output:       "9",
+		},
+		{
+			inputDistro:  "centos",
+			inputRelease: "stream9",
+			output:       "9",
+		},
+		{
+			inputDistro:  "centos",
+			inputRelease: "10",
+			output:       "10",
+		},
+		{
+			inputDistro:  "centos",
+			inputRelease: "9.4",
+			errMsg:       "The release must be a positive integer.",
+		},
+		{
+			inputDistro:  "centos",
+			inputRelease: "stream",
+			errMsg:       "The release must be a positive integer.",
+		},
+		{
+			inputDistro:  "centos",
+			inputRelease: "0",
+			errMsg:       "The release must be a positive integer.",
+		},
+		{
+			inputDistro:  "centos",
+			inputRelease: "-1",
+			errMsg:       "The release must be a positive integer.",
+		},
+		{
+			inputDistro:  "debian",
+			inputRelease: "12",
+			output:       "12",
+		},
+		{
+			inputDistro:  "debian",
+			inputRelease: "bookworm",
+			output:       "12",
+		},
+		{
+			inputDistro:  "debian",
+			inputRelease: "trixie",
+			output:       "13",
+		},
+		{
+			inputDistro:  "debian",
+			inputRelease: "testing",
+			output:       "testing",
+		},
+		{
+			inputDistro:  "debian",
+			inputRelease: "sid",
+			output:       "unstable",
+		},
+		{
+			inputDistro:  "debian",
+			inputRelease: "unstable",
+			output:       "unstable",
+		},
+		{
+			inputDistro:  "debian",
+			inputRelease: "",
+			errMsg:       "The release must be a number, a codename, 'testing' or 'unstable'.",
+		},
+		{
+			inputDistro:  "debian",
+			inputRelease: "foo",
+			errMsg:       "The release must be a number, a codename, 'testing' or 'unstable'.",
+		},
+		{
+			inputDistro:  "debian",
+			inputRelease: "12.1",
+			errMsg:       "The release must be a number, a codename, 'testing' or 'unstable'.",
+		},
+		{
+			inputDistro:  "debian",
+			inputRelease: "0",
+			errMsg:       "The release must be a positive integer.",
+		},
+		{
+			inputDistro:  "debian",
+			inputRelease: "-1",
+			errMsg:       "The release must be a positive integer.",
+		},
 		{
 			inputDistro:  "fedora",
 			inputRelease: "f34",
@@ -228,6 +955,26 @@ func TestParseRelease(t *testing.T) {
 			inputRelease: "2.-1",
 			errMsg:       "The release must be in the '<major>.<minor>' format.",
 		},
+		{
+			inputDistro:  "rocky",
+			inputRelease: "8",
+			output:       "8",
+		},
+		{
+			inputDistro:  "rocky",
+			inputRelease: "8.10",
+			output:       "8.10",
+		},
+		{
+			inputDistro:  "rocky",
+			inputRelease: "foo",
+			errMsg:       "The release must be in the '<major>' or '<major>.<minor>' format.",
+		},
+		{
+			inputDistro:  "rocky",
+			inputRelease: "0",
+			errMsg:       "The major release must be a positive integer.",
+		},
 		{
 			inputDistro:  "ubuntu",
 			inputRelease: "4.10",
@@ -365,6 +1112,115 @@ func TestParseRelease(t *testing.T) {
 	}
 }
 
+func TestReleaseLifetimes(t *testing.T) {
+	for distro, lifetimes := range releaseLifetimes {
+		for i, lifetime := range lifetimes {
+			release, err := parseRelease(distro, lifetime.Release)
+			assert.NoError(t, err)
+			assert.Equal(t, lifetime.Release, release)
+
+			if i > 0 {
+				assert.Less(t, CompareReleases(lifetimes[i-1].Release, lifetime.Release), 0)
+			}
+		}
+	}
+}
+
+func TestIsReleaseNumeric(t *testing.T) {
+	testCases := []struct {
+		release string
+		expect  bool
+	}{
+		{"40", true},
+		{"9.4", true},
+		{"24.04", true},
+		{"", false},
+		{"latest", false},
+		{"rawhide", false},
+		{"testing", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.release, func(t *testing.T) {
+			numeric := IsReleaseNumeric(tc.release)
+			assert.Equal(t, tc.expect, numeric)
+		})
+	}
+}
+
+func TestGetReleaseEndOfLife(t *testing.T) {
+	lifetimes, err := parseReleaseLifetimes([]byte(`{
+		"rhel": [
+			{ "release": "8.8", "end-of-life": "2025-05-31" },
+			{ "release": "8.10", "end-of-life": "2029-05-31" },
+			{ "release": "9.4", "end-of-life": "2026-04-30" },
+			{ "release": "9.5", "end-of-life": "2025-05-13" },
+			{ "release": "9.6" },
+			{ "release": "10.0" }
+		]
+	}`))
+	require.NoError(t, err)
+
+	now := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		release     string
+		endOfLife   string
+		replacement string
+	}{
+		{
+			release:     "8.8",
+			endOfLife:   "2025-05-31",
+			replacement: "8.10",
+		},
+		{
+			release: "8.10",
+		},
+		{
+			release:     "9.4",
+			endOfLife:   "2026-04-30",
+			replacement: "9.6",
+		},
+		{
+			release: "9.6",
+		},
+		{
+			release: "9.7",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.release, func(t *testing.T) {
+			endOfLife, replacement := getReleaseEndOfLife(lifetimes["rhel"], tc.release, now)
+			if tc.endOfLife == "" {
+				assert.True(t, endOfLife.IsZero())
+			} else {
+				assert.Equal(t, tc.endOfLife, endOfLife.Format(time.DateOnly))
+			}
+
+			assert.Equal(t, tc.replacement, replacement)
+		})
+	}
+
+	lifetimes, err = parseReleaseLifetimes([]byte(`{
+		"fedora": [
+			{ "release": "38", "end-of-life": "2024-05-21" },
+			{ "release": "44" }
+		]
+	}`))
+	require.NoError(t, err)
+
+	endOfLife, replacement := getReleaseEndOfLife(lifetimes["fedora"], "38", now)
+	assert.False(t, endOfLife.IsZero())
+	assert.Equal(t, "44", replacement)
+
+	_, err = parseReleaseLifetimes([]byte(`{ "fedora": [ { "release": "38", "end-of-life": "May 2024" } ] }`))
+	assert.Error(t, err)
+
+	_, err = parseReleaseLifetimes([]byte(`{ "foo": [ { "release": "1" } ] }`))
+	assert.Error(t, err)
+}
+
 func TestPathExistsDoesNotExist(t *testing.T) {
 	exists := PathExists("/does/not/exist")
 	assert.False(t, exists)
@@ -397,3 +1253,177 @@ func TestPathExistsSymlinkTargetExists(t *testing.T) {
 	exists := PathExists(link)
 	assert.True(t, exists)
 }
+
+func TestSetUpDistros(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config map[string]interface{}
+		err    string
+	}{
+		{
+			name: "Registry and image",
+			config: map[string]interface{}{
+				"distro.fedora.image":    "corp-fedora-toolbox",
+				"distro.fedora.registry": "registry.example.com/toolbx",
+			},
+		},
+		{
+			name: "New distribution",
+			config: map[string]interface{}{
+				"distro.mycorp.image":                "mycorp-toolbox",
+				"distro.mycorp.p11-kit-client-paths": []string{"/usr/lib64/pkcs11/p11-kit-client.so"},
+				"distro.mycorp.registry":             "registry.example.com",
+				"distro.mycorp.release-regex":        `[0-9]+`,
+				"distro.mycorp.release-required":     true,
+			},
+		},
+		{
+			name: "New distribution without an image",
+			config: map[string]interface{}{
+				"distro.mycorp.registry": "registry.example.com",
+			},
+			err: "distribution mycorp does not have an image",
+		},
+		{
+			name: "New distribution without a registry",
+			config: map[string]interface{}{
+				"distro.mycorp.image": "mycorp-toolbox",
+			},
+			err: "distribution mycorp does not have a registry",
+		},
+		{
+			name: "New distribution with an unknown option",
+			config: map[string]interface{}{
+				"distro.mycorp.image":    "mycorp-toolbox",
+				"distro.mycorp.registry": "registry.example.com",
+				"distro.mycorp.foo":      "bar",
+			},
+			err: "configuration for distribution mycorp has unknown or invalid keys",
+		},
+		{
+			name: "New distribution with an invalid release-regex",
+			config: map[string]interface{}{
+				"distro.mycorp.image":         "mycorp-toolbox",
+				"distro.mycorp.registry":      "registry.example.com",
+				"distro.mycorp.release-regex": "[0-9",
+			},
+			err: "release-regex for distribution mycorp is invalid",
+		},
+		{
+			name: "New distribution with the image of a built-in one",
+			config: map[string]interface{}{
+				"distro.mycorp.image":    "fedora-toolbox",
+				"distro.mycorp.registry": "registry.example.com",
+			},
+			err: "distribution mycorp has the same image as fedora",
+		},
+		{
+			name: "Built-in distribution with an option for new ones",
+			config: map[string]interface{}{
+				"distro.fedora.release-required": false,
+			},
+			err: "option release-required cannot be used for built-in distribution fedora",
+		},
+		{
+			name: "Registry without a domain",
+			config: map[string]interface{}{
+				"distro.fedora.registry": "toolbx",
+			},
+			err: "registry toolbx for distribution fedora does not have a domain",
+		},
+		{
+			name: "Image with a registry",
+			config: map[string]interface{}{
+				"distro.fedora.image": "registry.example.com/fedora-toolbox",
+			},
+			err: "image registry.example.com/fedora-toolbox for distribution fedora is not a basename",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				viper.Reset()
+				err := setUpDistros()
+				assert.NoError(t, err)
+			})
+
+			for key, value := range tc.config {
+				viper.Set(key, value)
+			}
+
+			err := setUpDistros()
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestSetUpDistrosRestoresDefaults(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	// Left behind by a user-defined distribution for the host
+	containerNamePrefixDefault = "mycorp"
+	distroDefault = "mycorp"
+	releaseDefault = "2.1"
+
+	err := setUpDistros()
+	require.NoError(t, err)
+
+	assert.Equal(t, builtInDefaults.containerNamePrefix, containerNamePrefixDefault)
+	assert.Equal(t, builtInDefaults.distro, distroDefault)
+	assert.Equal(t, builtInDefaults.release, releaseDefault)
+}
+
+func TestUserDistro(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Reset()
+		err := setUpDistros()
+		assert.NoError(t, err)
+	})
+
+	viper.Set("distro.mycorp.container-name-prefix", "mycorp")
+	viper.Set("distro.mycorp.image", "mycorp-toolbox")
+	viper.Set("distro.mycorp.registry", "registry.example.com/toolbx/")
+	viper.Set("distro.mycorp.release-regex", `[0-9]+\.[0-9]+`)
+	viper.Set("distro.mycorp.release-required", true)
+
+	err := setUpDistros()
+	require.NoError(t, err)
+
+	assert.Contains(t, GetSupportedDistros(), "mycorp")
+
+	container, image, release, err := ResolveContainerAndImageNames("", "mycorp", "", "2.1")
+	require.NoError(t, err)
+	assert.Equal(t, "mycorp-2.1", container)
+	assert.Equal(t, "mycorp-toolbox:2.1", image)
+	assert.Equal(t, "2.1", release)
+
+	imageFull, err := GetFullyQualifiedImageFromDistros(image, release)
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com/toolbx/mycorp-toolbox:2.1", imageFull)
+
+	distro, release := GetDistroAndReleaseForImage(imageFull)
+	assert.Equal(t, "mycorp", distro)
+	assert.Equal(t, "2.1", release)
+
+	_, _, _, err = ResolveContainerAndImageNames("", "mycorp", "", "foo")
+	var errParseRelease *ParseReleaseError
+	require.ErrorAs(t, err, &errParseRelease)
+	assert.Equal(t, `The release must match '[0-9]+\.[0-9]+'.`, errParseRelease.Hint)
+
+	_, _, _, err = ResolveContainerAndImageNames("", "mycorp", "", "")
+	assert.ErrorIs(t, err, ErrDistroWithoutRelease)
+
+	err = setUpDistros()
+	require.NoError(t, err)
+
+	viper.Reset()
+	err = setUpDistros()
+	require.NoError(t, err)
+
+	assert.NotContains(t, GetSupportedDistros(), "mycorp")
+}
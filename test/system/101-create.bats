@@ -116,6 +116,190 @@ teardown() {
   assert_output "true"
 }
 
+@test "create: With an idle timeout (using option --idle-timeout)" {
+  local default_container
+  default_container="$(get_system_id)-toolbox-$(get_system_version)"
+
+  pull_default_image
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --idle-timeout 30
+
+  assert_success
+  assert_line --index 0 "Created container: $default_container"
+  assert_line --index 1 "Enter with: toolbox enter"
+  assert [ ${#lines[@]} -eq 2 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+
+  run podman inspect \
+        --format '{{json .Config.Cmd}}' \
+        --type container \
+        "$default_container"
+
+  assert_success
+  assert_output --partial '"--idle-timeout","30"'
+}
+
+@test "create: With extra volumes (using options --volume and --mount)" {
+  local default_container
+  default_container="$(get_system_id)-toolbox-$(get_system_version)"
+
+  pull_default_image
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create \
+    --volume "$BATS_TEST_TMPDIR:/srv/data:ro" \
+    --mount type=tmpfs,destination=/var/tmp/toolbx-test
+
+  assert_success
+  assert_line --index 0 "Created container: $default_container"
+  assert_line --index 1 "Enter with: toolbox enter"
+  assert [ ${#lines[@]} -eq 2 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+
+  run podman inspect \
+        --format '{{range .Mounts}}{{.Destination}}{{"\n"}}{{end}}' \
+        --type container \
+        "$default_container"
+
+  assert_success
+  assert_line "/srv/data"
+
+  run podman inspect \
+        --format '{{json .HostConfig.Tmpfs}}' \
+        --type container \
+        "$default_container"
+
+  assert_success
+  assert_output --partial '/var/tmp/toolbx-test'
+}
+
+@test "create: With a separate home directory (using options --isolated-home and --home-share)" {
+  local default_container
+  default_container="$(get_system_id)-toolbox-$(get_system_version)"
+
+  local home_dir="$XDG_DATA_HOME/toolbox/$default_container/home"
+
+  pull_default_image
+  mkdir --parents "$HOME/src"
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --isolated-home --home-share src
+
+  assert_success
+  assert_line --index 0 "Created container: $default_container"
+  assert_line --index 1 "Enter with: toolbox enter"
+  assert [ ${#lines[@]} -eq 2 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+  assert [ -d "$home_dir" ]
+
+  run podman inspect \
+        --format '{{index .Config.Labels "com.github.containers.toolbox.home"}}' \
+        --type container \
+        "$default_container"
+
+  assert_success
+  assert_output "$(readlink --canonicalize "$home_dir")"
+
+  run podman inspect \
+        --format '{{range .Mounts}}{{.Destination}}{{"\n"}}{{end}}' \
+        --type container \
+        "$default_container"
+
+  assert_success
+  assert_line "$(readlink --canonicalize "$home_dir")"
+  assert_line "$(readlink --canonicalize "$home_dir")/src"
+  refute_line "$(readlink --canonicalize "$HOME")"
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" run sh -c 'echo "$HOME"; getent passwd "$USER" | cut --delimiter : --fields 6'
+
+  assert_success
+  assert_line --index 0 "$(readlink --canonicalize "$home_dir")"
+  assert_line --index 1 "$(readlink --canonicalize "$home_dir")"
+  assert [ ${#lines[@]} -eq 2 ]
+}
+
+@test "create: With separate XDG directories (using option --isolated-xdg-dirs)" {
+  local default_container
+  default_container="$(get_system_id)-toolbox-$(get_system_version)"
+
+  local xdg_dirs="$XDG_DATA_HOME/toolbox/$default_container"
+
+  pull_default_image
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --isolated-xdg-dirs
+
+  assert_success
+  assert_line --index 0 "Created container: $default_container"
+  assert_line --index 1 "Enter with: toolbox enter"
+  assert [ ${#lines[@]} -eq 2 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" run \
+    printenv HOME XDG_CACHE_HOME XDG_CONFIG_HOME XDG_DATA_HOME XDG_STATE_HOME CARGO_HOME HISTFILE
+
+  assert_success
+  assert_line --index 0 "$HOME"
+  assert_line --index 1 "$xdg_dirs/cache"
+  assert_line --index 2 "$xdg_dirs/config"
+  assert_line --index 3 "$xdg_dirs/data"
+  assert_line --index 4 "$xdg_dirs/state"
+  assert_line --index 5 "$xdg_dirs/cargo"
+  assert_line --index 6 "$xdg_dirs/state/history"
+  assert [ ${#lines[@]} -eq 7 ]
+  assert [ -d "$xdg_dirs/config" ]
+  assert [ -d "$xdg_dirs/cargo" ]
+}
+
+@test "create: With init hooks (using option --init-hooks)" {
+  local hooks="$BATS_TEST_TMPDIR/init.d"
+
+  mkdir --parents "$hooks/once" "$hooks/start-user"
+
+  cat >"$hooks/once/10-once" <<'HOOK'
+#!/bin/sh
+echo once >>/var/lib/toolbx-init-hooks
+HOOK
+
+  cat >"$hooks/start-user/10-start" <<'HOOK'
+#!/bin/sh
+echo "start as $(id -u)"
+HOOK
+
+  chmod 755 "$hooks/once/10-once" "$hooks/start-user/10-start"
+
+  pull_default_image
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --init-hooks "$hooks" hooks
+
+  assert_success
+  assert_line --index 0 "Created container: hooks"
+
+  container_started hooks
+  stop_container hooks
+  container_started hooks
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" run --container hooks cat /var/lib/toolbx-init-hooks
+
+  assert_success
+  assert_line --index 0 "once"
+  assert [ ${#lines[@]} -eq 1 ]
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" logs hooks
+
+  assert_success
+  assert_line "INFO    10-start: start as $(id -u)"
+}
+
+@test "create: Try a non-existent directory with init hooks" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --init-hooks "$BATS_TEST_TMPDIR/init.d"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: invalid argument for '--init-hooks'"
+  assert_line --index 1 "directory $BATS_TEST_TMPDIR/init.d not found"
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
 @test "create: With a custom image and name (using option --container)" {
   pull_distro_image fedora 34
 
@@ -604,6 +788,219 @@ teardown() {
   assert [ ${#stderr_lines[@]} -eq 3 ]
 }
 
+@test "create: With a custom image from an archive" {
+  local archive="$BATS_TEST_TMPDIR/image.tar"
+  local image="localhost/toolbx-archive:1"
+
+  echo -e "FROM scratch\n\nLABEL com.github.containers.toolbox=\"true\"" > "$BATS_TEST_TMPDIR"/Containerfile
+
+  run podman build --quiet --tag "$image" "$BATS_TEST_TMPDIR"
+  assert_success
+
+  run podman save --format docker-archive --output "$archive" "$image"
+  assert_success
+
+  run podman rmi "$image"
+  assert_success
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --image "docker-archive:$archive"
+
+  assert_success
+  assert_line --index 0 "Created container: toolbx-archive-1"
+  assert_line --index 1 "Enter with: toolbox enter toolbx-archive-1"
+  assert [ ${#lines[@]} -eq 2 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+
+  run podman image exists "$image"
+  assert_success
+}
+
+@test "create: Try a non-existent archive" {
+  local archive="$BATS_TEST_TMPDIR/non-existent-image.tar"
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --image "oci-archive:$archive"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: failed to load image oci-archive:$archive"
+  assert_line --index 1 "Use 'toolbox --verbose ...' for further details."
+  assert [ ${#stderr_lines[@]} -eq 2 ]
+}
+
+@test "create: Try an archive with an invalid container name" {
+  local archive="$BATS_TEST_TMPDIR/image.tar"
+  local image="localhost/toolbx-archive:1"
+
+  echo -e "FROM scratch\n\nLABEL com.github.containers.toolbox=\"true\"" > "$BATS_TEST_TMPDIR"/Containerfile
+
+  run podman build --quiet --tag "$image" "$BATS_TEST_TMPDIR"
+  assert_success
+
+  run podman save --format docker-archive --output "$archive" "$image"
+  assert_success
+
+  run podman rmi "$image"
+  assert_success
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --image "docker-archive:$archive" "ßpeci@l.Nam€"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: invalid argument for 'CONTAINER'"
+  assert_line --index 1 "Container names must match '[a-zA-Z0-9][a-zA-Z0-9_.-]*'."
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+
+  run podman image exists "$image"
+  assert_failure
+}
+
+@test "create: Try an archive with an image that is not a Toolbx image" {
+  local archive="$BATS_TEST_TMPDIR/image.tar"
+  local image="localhost/toolbx-archive:1"
+
+  echo -e "FROM scratch\n\nLABEL foo=\"bar\"" > "$BATS_TEST_TMPDIR"/Containerfile
+
+  run podman build --quiet --tag "$image" "$BATS_TEST_TMPDIR"
+  assert_success
+
+  run podman save --format docker-archive --output "$archive" "$image"
+  assert_success
+
+  run podman rmi "$image"
+  assert_success
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --image "docker-archive:$archive"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: image docker-archive:$archive is not a Toolbx image"
+  assert [ ${#stderr_lines[@]} -eq 1 ]
+
+  run podman image exists "$image"
+  assert_failure
+}
+
+@test "create: Try an archive with an image that is not a Toolbx image and is already present" {
+  local archive="$BATS_TEST_TMPDIR/image.tar"
+  local image="localhost/toolbx-archive:1"
+
+  echo -e "FROM scratch\n\nLABEL foo=\"bar\"" > "$BATS_TEST_TMPDIR"/Containerfile
+
+  run podman build --quiet --tag "$image" "$BATS_TEST_TMPDIR"
+  assert_success
+
+  run podman save --format docker-archive --output "$archive" "$image"
+  assert_success
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --image "docker-archive:$archive"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: image docker-archive:$archive is not a Toolbx image"
+  assert [ ${#stderr_lines[@]} -eq 1 ]
+
+  run podman image exists "$image"
+  assert_success
+
+  run podman rmi "$image"
+  assert_success
+}
+
+@test "create: Try Fedora from a non-existent mirror in toolbox.conf (using --assumeyes)" {
+  local config_file="$XDG_CONFIG_HOME/containers/toolbox.conf"
+
+  mkdir --parents "$XDG_CONFIG_HOME/containers"
+  cat <<EOF_CONFIG >"$config_file"
+[distro.fedora]
+registry = "foo.org/mirror"
+EOF_CONFIG
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" --assumeyes create --distro fedora --release 34
+
+  rm "$config_file"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: failed to pull image foo.org/mirror/fedora-toolbox:34"
+  assert_line --index 1 "If it was a private image, log in with: podman login foo.org"
+  assert_line --index 2 "Use 'toolbox --verbose ...' for further details."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
+@test "create: Try a distribution without an image in toolbox.conf" {
+  local config_file="$XDG_CONFIG_HOME/containers/toolbox.conf"
+
+  mkdir --parents "$XDG_CONFIG_HOME/containers"
+  cat <<EOF_CONFIG >"$config_file"
+[distro.foo]
+registry = "foo.org"
+EOF_CONFIG
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" --assumeyes create
+
+  rm "$config_file"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: distribution foo does not have an image"
+  assert [ ${#stderr_lines[@]} -eq 1 ]
+}
+
+@test "create: Try a distribution from toolbox.conf with an invalid release" {
+  local config_file="$XDG_CONFIG_HOME/containers/toolbox.conf"
+
+  mkdir --parents "$XDG_CONFIG_HOME/containers"
+  cat <<EOF_CONFIG >"$config_file"
+[distro.foo]
+image = "foo-toolbox"
+registry = "foo.org"
+release-regex = "[0-9]+"
+release-required = true
+EOF_CONFIG
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" --assumeyes create --distro foo --release bar
+
+  rm "$config_file"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: invalid argument for '--release'"
+  assert_line --index 1 "The release must match '[0-9]+'."
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
+@test "create: Try a distribution from toolbox.conf from a non-existent registry (using --assumeyes)" {
+  local config_file="$XDG_CONFIG_HOME/containers/toolbox.conf"
+
+  mkdir --parents "$XDG_CONFIG_HOME/containers"
+  cat <<EOF_CONFIG >"$config_file"
+[distro.foo]
+image = "foo-toolbox"
+registry = "foo.org"
+EOF_CONFIG
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" --assumeyes create --distro foo
+
+  rm "$config_file"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: failed to pull image foo.org/foo-toolbox:latest"
+  assert_line --index 1 "If it was a private image, log in with: podman login foo.org"
+  assert_line --index 2 "Use 'toolbox --verbose ...' for further details."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
 @test "create: Try Arch Linux with an invalid release ('--release foo')" {
   run --keep-empty-lines --separate-stderr "$TOOLBX" --assumeyes create --distro arch --release foo
 
@@ -856,6 +1253,18 @@ teardown() {
   assert [ ${#stderr_lines[@]} -eq 3 ]
 }
 
+@test "create: Try Rocky Linux with an invalid release ('--release foo')" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" --assumeyes create --distro rocky --release foo
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: invalid argument for '--release'"
+  assert_line --index 1 "The release must be in the '<major>' or '<major>.<minor>' format."
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
 @test "create: Try Ubuntu with an invalid release ('--release 20')" {
   run --keep-empty-lines --separate-stderr "$TOOLBX" --assumeyes create --distro ubuntu --release 20
 
@@ -1084,6 +1493,54 @@ teardown() {
   assert [ ${#stderr_lines[@]} -eq 3 ]
 }
 
+@test "create: Try AlmaLinux with an invalid release ('--release 9.4.1')" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" --assumeyes create --distro almalinux --release 9.4.1
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: invalid argument for '--release'"
+  assert_line --index 1 "The release must be in the '<major>' or '<major>.<minor>' format."
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
+@test "create: Try CentOS Stream with an invalid release ('--release 9.4')" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" --assumeyes create --distro centos --release 9.4
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: invalid argument for '--release'"
+  assert_line --index 1 "The release must be a positive integer."
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
+@test "create: Try Debian with an invalid release ('--release foo')" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" --assumeyes create --distro debian --release foo
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: invalid argument for '--release'"
+  assert_line --index 1 "The release must be a number, a codename, 'testing' or 'unstable'."
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
+@test "create: Try Debian with an invalid release ('--release 0')" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" --assumeyes create --distro debian --release 0
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: invalid argument for '--release'"
+  assert_line --index 1 "The release must be a positive integer."
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
 @test "create: Try a non-default distro without a release" {
   local distro="fedora"
 
@@ -1177,6 +1634,77 @@ teardown() {
   assert [ ${#stderr_lines[@]} -eq 3 ]
 }
 
+@test "create: Try using both --home and --isolated-home" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --home "$BATS_TEST_TMPDIR/home" --isolated-home
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: options --home and --isolated-home cannot be used together"
+  assert_line --index 1 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 2 ]
+}
+
+@test "create: Try using --home-share without a separate home directory" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --home-share src
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: option --home-share needs --home or --isolated-home"
+  assert_line --index 1 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 2 ]
+}
+
+@test "create: Try using both --file and --distro" {
+  echo 'name = "manifest"' >"$BATS_TEST_TMPDIR/toolbox.toml"
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --file "$BATS_TEST_TMPDIR/toolbox.toml" --distro fedora
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: options --file and --distro cannot be used together"
+  assert_line --index 1 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 2 ]
+}
+
+@test "create: Try a non-existent manifest" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --file "$BATS_TEST_TMPDIR/toolbox.toml"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: invalid argument for '--file'"
+  assert_line --index 1 "file $BATS_TEST_TMPDIR/toolbox.toml not found"
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
+@test "create: Try a volume that clashes with a Toolbx mount" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --volume "$BATS_TEST_TMPDIR:/run/host/srv"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: invalid volume: destination /run/host/srv is inside /run/host used by Toolbx"
+  assert [ ${#stderr_lines[@]} -eq 1 ]
+}
+
+@test "create: Try a volume with a non-existent source" {
+  local path="$BATS_TEST_TMPDIR/non-existent-path"
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --volume "$path:/srv/data"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: invalid argument for '--volume'"
+  assert_line --index 1 "source $path not found"
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
 @test "create: With a custom image that needs an authentication file" {
   local authfile="$BATS_TEST_TMPDIR/authfile"
   local image="fedora-toolbox:34"
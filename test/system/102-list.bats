@@ -592,3 +592,132 @@ teardown() {
   assert [ ${#lines[@]} -eq 0 ]
   assert [ ${#stderr_lines[@]} -eq 0 ]
 }
+
+@test "list: Smoke test (using --format json)" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" list --format json
+
+  assert_success
+  assert_line --index 0 "{"
+  assert_line --index 1 '  "containers": [],'
+  assert_line --index 2 '  "images": []'
+  assert_line --index 3 "}"
+  assert [ ${#lines[@]} -eq 4 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
+@test "list: Containers and images (using --format json)" {
+  local default_image
+  default_image="$(get_default_image)"
+
+  local default_container
+  default_container="$(get_system_id)-toolbox-$(get_system_version)"
+
+  pull_default_image
+  create_default_container
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" list --format json
+
+  assert_success
+  assert_output --partial "\"$default_container\""
+  assert_output --partial "\"$default_image\""
+  assert_output --partial '"status": "created"'
+  assert_output --partial '"com.github.containers.toolbox": "true"'
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
+@test "list: Containers (using --format with a Go template)" {
+  local default_container
+  default_container="$(get_system_id)-toolbox-$(get_system_version)"
+
+  pull_default_image
+  create_default_container
+  create_container non-default-one
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" list \
+        --containers \
+        --format '{{index .Names 0}} {{.Status}}'
+
+  assert_success
+  assert_line "$default_container created"
+  assert_line "non-default-one created"
+  assert [ ${#lines[@]} -eq 2 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
+@test "list: Images (using --format with a Go template)" {
+  local default_image
+  default_image="$(get_default_image)"
+
+  pull_default_image
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" list --images --format '{{json .Names}}'
+
+  assert_success
+  assert_line --index 0 "[\"$default_image\"]"
+  assert [ ${#lines[@]} -eq 1 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
+@test "list: Try an invalid format (using --format foo)" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" list --format foo
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: invalid argument for '--format'"
+  assert_line --index 1 "Supported values are 'json', 'table' and Go templates."
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
+@test "list: Containers by status (using --filter status=running)" {
+  pull_default_image
+  create_container running
+  create_container not-running
+  start_container running
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" list --filter status=running
+
+  assert_success
+  assert_line --index 1 --partial "running"
+  assert [ ${#lines[@]} -eq 2 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
+@test "list: Images by release (using --filter release=34)" {
+  pull_default_image
+  pull_distro_image fedora 34
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" list --images --filter release=34
+
+  assert_success
+  assert_line --index 1 --partial "registry.fedoraproject.org/fedora-toolbox:34"
+  assert [ ${#lines[@]} -eq 2 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
+@test "list: Smoke test (using --check-updates)" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" list --check-updates
+
+  assert_success
+  assert [ ${#lines[@]} -eq 0 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
+@test "list: End-of-life image (using Ubuntu 16.04)" {
+  pull_distro_image ubuntu 16.04
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" list --images
+
+  assert_success
+  assert_line --index 1 --partial "quay.io/toolbx/ubuntu-toolbox:16.04 (end-of-life)"
+  assert [ ${#lines[@]} -eq 2 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" list --images --format '{{.EndOfLife}}'
+
+  assert_success
+  assert_line --index 0 "true"
+  assert [ ${#lines[@]} -eq 1 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
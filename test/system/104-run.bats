@@ -210,6 +210,44 @@ teardown() {
   assert [ ${#stderr_lines[@]} -eq 0 ]
 }
 
+@test "run: Ensure that environment variables from 'create --env' and '--env-file' are set" {
+  pull_default_image
+
+  local env_file="$BATS_TEST_TMPDIR/env"
+  printf '# Build settings\nCC=clang\nGOFLAGS=-mod=mod\n' >"$env_file"
+
+  "$TOOLBX" create --env GOFLAGS=-mod=vendor --env-file "$env_file" >/dev/null
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" run printenv CC GOFLAGS
+
+  assert_success
+  assert_line --index 0 "clang"
+  assert_line --index 1 "-mod=vendor"
+  assert [ ${#lines[@]} -eq 2 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
+@test "run: Override an environment variable (using option --env)" {
+  pull_default_image
+
+  "$TOOLBX" create --env CC=clang >/dev/null
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" run --env CC=gcc --env TOOLBX_TEST=foo printenv CC TOOLBX_TEST
+
+  assert_success
+  assert_line --index 0 "gcc"
+  assert_line --index 1 "foo"
+  assert [ ${#lines[@]} -eq 2 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" run printenv CC
+
+  assert_success
+  assert_line --index 0 "clang"
+  assert [ ${#lines[@]} -eq 1 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
 @test "run: Ensure that /run/.containerenv exists" {
   create_default_container
 
@@ -314,6 +352,37 @@ teardown() {
   assert [ ${#stderr_lines[@]} -gt 2 ]
 }
 
+@test "run: Ensure that the separate home directory is used as a fallback working directory" {
+  local default_container_name
+  default_container_name="$(get_system_id)-toolbox-$(get_system_version)"
+
+  local home_dir="$XDG_DATA_HOME/toolbox/$default_container_name/home"
+
+  pull_default_image
+  "$TOOLBX" create --isolated-home
+
+  local host_only_dir
+  host_only_dir="$(mktemp --directory /var/tmp/toolbx-test-XXXXXXXXXX)"
+
+  pushd "$host_only_dir"
+  run --keep-empty-lines --separate-stderr "$TOOLBX" run pwd
+  popd
+
+  rm --force --recursive "$host_only_dir"
+
+  local home_dir_evaled
+  home_dir_evaled="$(readlink --canonicalize "$home_dir")"
+
+  assert_success
+  assert_line --index 0 "$home_dir_evaled"
+  assert [ ${#lines[@]} -eq 1 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index $((${#stderr_lines[@]}-2)) \
+    "Error: directory $host_only_dir not found in container $default_container_name"
+  assert_line --index $((${#stderr_lines[@]}-1)) "Using $home_dir_evaled instead."
+  assert [ ${#stderr_lines[@]} -gt 2 ]
+}
+
 @test "run: Pass down 1 additional file descriptor" {
   create_default_container
 
@@ -810,6 +879,71 @@ teardown() {
   assert_output ""
 }
 
+@test "run: Host hooks from toolbox.conf" {
+  local config_file="$XDG_CONFIG_HOME/containers/toolbox.conf"
+  local hooks_log="$BATS_TEST_TMPDIR/hooks.log"
+
+  create_default_container
+
+  mkdir --parents "$XDG_CONFIG_HOME/containers"
+  cat <<EOF_CONFIG >"$config_file"
+[hooks]
+pre-start = 'echo "\$TOOLBX_HOOK \$TOOLBX_CONTAINER" >>$hooks_log'
+post-start = 'echo "\$TOOLBX_HOOK \$TOOLBX_CONTAINER" >>$hooks_log'
+pre-enter = 'echo "\$TOOLBX_HOOK \$TOOLBX_CONTAINER" >>$hooks_log'
+post-exit = 'echo "\$TOOLBX_HOOK \$TOOLBX_CONTAINER" >>$hooks_log'
+EOF_CONFIG
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" run true
+
+  assert_success
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" run true
+
+  rm "$config_file"
+
+  assert_success
+
+  local default_container
+  default_container="$(get_system_id)-toolbox-$(get_system_version)"
+
+  run cat "$hooks_log"
+
+  assert_success
+  assert_line --index 0 "pre-start $default_container"
+  assert_line --index 1 "post-start $default_container"
+  assert_line --index 2 "pre-enter $default_container"
+  assert_line --index 3 "post-exit $default_container"
+  assert_line --index 4 "pre-enter $default_container"
+  assert_line --index 5 "post-exit $default_container"
+  assert [ ${#lines[@]} -eq 6 ]
+}
+
+@test "run: Try a failing pre-enter hook from toolbox.conf" {
+  local config_file="$XDG_CONFIG_HOME/containers/toolbox.conf"
+
+  create_default_container
+
+  local default_container
+  default_container="$(get_system_id)-toolbox-$(get_system_version)"
+
+  mkdir --parents "$XDG_CONFIG_HOME/containers"
+  cat <<EOF_CONFIG >"$config_file"
+[hooks]
+pre-enter = "exit 1"
+EOF_CONFIG
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" run echo foo
+
+  rm "$config_file"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: pre-enter hook failed for container $default_container"
+  assert [ ${#stderr_lines[@]} -eq 1 ]
+}
+
 @test "run: Pass down 1 invalid file descriptor" {
   local default_container_name
   default_container_name="$(get_system_id)-toolbox-$(get_system_version)"
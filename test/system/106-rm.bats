@@ -86,3 +86,64 @@ teardown() {
 
   assert_equal "$new_num_of_containers" "$num_of_containers"
 }
+
+@test "rm: Remove only stopped containers (using --filter status=created)" {
+  create_container running
+  create_container not-running
+  start_container running
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" rm --filter status=created
+
+  assert_success
+  assert [ ${#lines[@]} -eq 0 ]
+
+  # shellcheck disable=SC2154
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+
+  run podman ps --all --format '{{.Names}}'
+
+  assert_success
+  assert_output "running"
+}
+
+@test "rm: Remove containers by name (using --filter name=GLOB)" {
+  create_container foo-one
+  create_container foo-two
+  create_container bar
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" rm --filter 'name=foo-*'
+
+  assert_success
+  assert [ ${#lines[@]} -eq 0 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+
+  run podman ps --all --format '{{.Names}}'
+
+  assert_success
+  assert_output "bar"
+}
+
+@test "rm: Try --filter with a container name" {
+  create_container foo
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" rm --filter status=created foo
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: option --filter cannot be used with container names"
+  assert_line --index 1 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 2 ]
+}
+
+@test "rm: Try an invalid filter (using --filter foo=bar)" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" rm --filter foo=bar
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: invalid argument for '--filter'"
+  assert_line --index 1 "filter key foo is unsupported"
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
@@ -266,3 +266,29 @@ teardown() {
 
   assert_equal "$new_num_of_images" "$num_of_images"
 }
+
+@test "rmi: Images by distro (using --filter distro=fedora)" {
+  pull_distro_image fedora 34
+  pull_distro_image rhel 8.10
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" rmi --filter distro=fedora
+
+  assert_success
+  assert [ ${#lines[@]} -eq 0 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+
+  local num_of_images
+  num_of_images="$(list_images)"
+  assert_equal "$num_of_images" 1
+}
+
+@test "rmi: Try a filter that only applies to containers (using --filter status=running)" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" rmi --filter status=running
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: filter status cannot be used with images"
+  assert_line --index 1 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 2 ]
+}
@@ -0,0 +1,102 @@
+# shellcheck shell=bats
+#
+# Copyright © 2026 Red Hat, Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+
+# bats file_tags=commands-options
+
+load 'libs/bats-support/load'
+load 'libs/bats-assert/load'
+load 'libs/helpers'
+
+setup() {
+  bats_require_minimum_version 1.10.0
+  cleanup_all
+}
+
+teardown() {
+  cleanup_all
+}
+
+
+@test "stop: Try without any arguments" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" stop
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: missing argument for \"stop\""
+  assert_line --index 1 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 2 ]
+}
+
+@test "stop: Try to stop a non-existent container" {
+  container_name="nonexistentcontainer"
+  run --keep-empty-lines --separate-stderr "$TOOLBX" stop "$container_name"
+
+  assert_success
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: failed to inspect container $container_name"
+  assert [ ${#stderr_lines[@]} -eq 1 ]
+}
+
+@test "stop: A running container" {
+  create_container running
+  container_started running
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" stop running
+
+  assert_success
+  assert [ ${#lines[@]} -eq 0 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+
+  run podman inspect --format '{{.State.Status}}' --type container running
+
+  assert_success
+  assert_output "exited"
+
+  run podman inspect --format '{{.State.ExitCode}}' --type container running
+
+  assert_success
+  assert_output "0"
+}
+
+@test "stop: A container that is not running" {
+  create_container not-running
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" stop not-running
+
+  assert_success
+  assert [ ${#lines[@]} -eq 0 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
+@test "stop: All containers (with 2 containers created and 1 running)" {
+  create_container running
+  create_container not-running
+  container_started running
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" stop --all
+
+  assert_success
+  assert [ ${#lines[@]} -eq 0 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+
+  run podman inspect --format '{{.State.Status}}' --type container running
+
+  assert_success
+  assert_output "exited"
+}
@@ -0,0 +1,106 @@
+# shellcheck shell=bats
+#
+# Copyright © 2026 Red Hat, Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+
+# bats file_tags=commands-options
+
+load 'libs/bats-support/load'
+load 'libs/bats-assert/load'
+load 'libs/helpers'
+
+setup() {
+  bats_require_minimum_version 1.10.0
+  cleanup_all
+}
+
+teardown() {
+  cleanup_all
+}
+
+
+@test "inspect: Try a non-existent container" {
+  container_name="nonexistentcontainer"
+  run --keep-empty-lines --separate-stderr "$TOOLBX" inspect "$container_name"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: container $container_name not found"
+  assert_line --index 1 "Use the 'create' command to create a Toolbx."
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
+@test "inspect: Try an invalid format (using --format foo)" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" inspect --format foo
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: invalid argument for '--format'"
+  assert_line --index 1 "Supported values are 'json' and 'text'."
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
+@test "inspect: A container that is not running" {
+  local default_image
+  default_image="$(get_default_image)"
+
+  create_container not-running
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" inspect not-running
+
+  assert_success
+  assert_line --index 0 --regexp "^Name:[[:blank:]]+not-running$"
+  assert_line --index 2 --regexp "^Image:[[:blank:]]+$default_image$"
+  assert_line --index 3 --regexp "^Distribution:[[:blank:]]+$(get_system_id)$"
+  assert_line --index 6 --regexp "^Status:[[:blank:]]+created$"
+  assert_line --index 7 --regexp "^Entry point PID:[[:blank:]]+none$"
+  assert_line --index 8 --regexp "^Initialized:[[:blank:]]+no$"
+  assert_line --index 11 "Mounts:"
+  assert_output --regexp "/run/host[[:blank:]]+host"
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
+@test "inspect: A running container" {
+  create_container running
+  container_started running
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" inspect running
+
+  assert_success
+  assert_line --index 6 --regexp "^Status:[[:blank:]]+running$"
+  assert_line --index 7 --regexp "^Entry point PID:[[:blank:]]+[0-9]+$"
+  assert_line --index 8 --regexp "^Initialized:[[:blank:]]+yes$"
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
+@test "inspect: A running container (using --format json)" {
+  create_container running
+  container_started running
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" inspect --format json running
+
+  assert_success
+  assert_line --index 0 "{"
+  assert_line --index 1 '  "name": "running",'
+  assert_output --partial '"status": "running",'
+  assert_output --partial '"initialized": true,'
+  assert_output --partial '"destination": "/run/host",'
+  assert_output --partial '"kind": "host"'
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
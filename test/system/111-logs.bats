@@ -0,0 +1,89 @@
+# shellcheck shell=bats
+#
+# Copyright © 2026 Red Hat, Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+
+# bats file_tags=commands-options
+
+load 'libs/bats-support/load'
+load 'libs/bats-assert/load'
+load 'libs/helpers'
+
+setup() {
+  bats_require_minimum_version 1.10.0
+  cleanup_all
+}
+
+teardown() {
+  cleanup_all
+}
+
+
+@test "logs: Try a non-existent container" {
+  container_name="nonexistentcontainer"
+  run --keep-empty-lines --separate-stderr "$TOOLBX" logs "$container_name"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: container $container_name not found"
+  assert_line --index 1 "Use the 'create' command to create a Toolbx."
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
+@test "logs: Try an invalid time (using --since foo)" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" logs --since foo
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: invalid argument for '--since'"
+  assert_line --index 1 "Use a duration like 10m or a time like 2006-01-02T15:04:05Z."
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
+@test "logs: A container that was never started" {
+  create_container not-running
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" logs not-running
+
+  assert_success
+  assert [ ${#lines[@]} -eq 0 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
+@test "logs: A running container" {
+  create_container running
+  container_started running
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" logs running
+
+  assert_success
+  assert_line "DEBUG   Listening to file system and ticker events"
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
+@test "logs: A running container (using --raw)" {
+  create_container running
+  container_started running
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" logs --raw running
+
+  assert_success
+  assert_line 'level=debug msg="Listening to file system and ticker events"'
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
@@ -0,0 +1,78 @@
+# shellcheck shell=bats
+#
+# Copyright © 2026 Red Hat, Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+
+# bats file_tags=commands-options
+
+load 'libs/bats-support/load'
+load 'libs/bats-assert/load'
+load 'libs/helpers'
+
+setup() {
+  bats_require_minimum_version 1.10.0
+  cleanup_all
+}
+
+teardown() {
+  cleanup_all
+}
+
+@test "doctor: Smoke test" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" doctor
+
+  assert_success
+  assert_line --index 0 --regexp '^PASS +Podman +version [0-9]+'
+  assert_line --regexp '^(PASS|WARN) +cgroups +version [12]$'
+  assert_line --regexp '^PASS +D-Bus system socket +/.+$'
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
+@test "doctor: Remove stale runtime files" {
+  local runtime_dir="${XDG_RUNTIME_DIR:-/run/user/$(id -u)}/toolbox"
+  local stamp="$runtime_dir/container-initialized-999999999"
+  local sessions="$runtime_dir/container-sessions-999999999"
+
+  mkdir --parents "$sessions"
+  touch "$stamp"
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" doctor
+
+  assert_success
+  assert_line --regexp '^WARN +Runtime files +2 stale$'
+  assert_line --regexp "^ +$stamp$"
+  assert_line --regexp "^ +$sessions$"
+  assert [ -e "$stamp" ]
+  assert [ -d "$sessions" ]
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" doctor --fix
+
+  assert_success
+  assert_line --regexp '^PASS +Runtime files +2 stale removed$'
+  assert [ ! -e "$stamp" ]
+  assert [ ! -e "$sessions" ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
+@test "doctor: Try with an argument" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" doctor foo
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: too many arguments for \"doctor\""
+  assert_line --index 1 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 2 ]
+}
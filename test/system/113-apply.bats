@@ -0,0 +1,218 @@
+# shellcheck shell=bats
+#
+# Copyright © 2026 Red Hat, Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+
+# bats file_tags=commands-options
+
+load 'libs/bats-support/load'
+load 'libs/bats-assert/load'
+load 'libs/helpers'
+
+setup() {
+  bats_require_minimum_version 1.10.0
+  cleanup_all
+}
+
+teardown() {
+  cleanup_all
+}
+
+
+@test "apply: Create a Toolbx container from a manifest" {
+  pull_default_image
+
+  cat >"$BATS_TEST_TMPDIR/toolbox.toml" <<'MANIFEST'
+name = "apply-test"
+env = ["TOOLBX_TEST_APPLY=foo"]
+setup = ["echo applied >/tmp/toolbx-apply"]
+MANIFEST
+
+  pushd "$BATS_TEST_TMPDIR" || return 1
+  run --keep-empty-lines --separate-stderr "$TOOLBX" apply
+  popd || return 1
+
+  assert_success
+  assert_line "Running in container apply-test: echo applied >/tmp/toolbx-apply"
+  assert_line "Created container: apply-test"
+  assert_line "Enter with: toolbox enter apply-test"
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" run --container apply-test \
+    sh -c 'printenv TOOLBX_TEST_APPLY; cat /tmp/toolbx-apply'
+
+  assert_success
+  assert_line --index 0 "foo"
+  assert_line --index 1 "applied"
+  assert [ ${#lines[@]} -eq 2 ]
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
+@test "apply: Don't run unchanged setup commands again in an existing Toolbx container" {
+  pull_default_image
+
+  cat >"$BATS_TEST_TMPDIR/toolbox.yaml" <<'MANIFEST'
+name: apply-test
+setup:
+  - echo applied >>/tmp/toolbx-apply
+MANIFEST
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --file "$BATS_TEST_TMPDIR/toolbox.yaml"
+
+  assert_success
+  assert_line "Created container: apply-test"
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" apply --file "$BATS_TEST_TMPDIR/toolbox.yaml"
+
+  assert_success
+  refute_line "Created container: apply-test"
+  refute_line "Running in container apply-test: echo applied >>/tmp/toolbx-apply"
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" run --container apply-test cat /tmp/toolbx-apply
+
+  assert_success
+  assert_line --index 0 "applied"
+  assert [ ${#lines[@]} -eq 1 ]
+}
+
+@test "apply: Run the setup commands again in an existing Toolbx container after they changed" {
+  pull_default_image
+
+  cat >"$BATS_TEST_TMPDIR/toolbox.yaml" <<'MANIFEST'
+name: apply-test
+setup:
+  - echo applied >>/tmp/toolbx-apply
+MANIFEST
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --file "$BATS_TEST_TMPDIR/toolbox.yaml"
+
+  assert_success
+  assert_line "Created container: apply-test"
+
+  cat >"$BATS_TEST_TMPDIR/toolbox.yaml" <<'MANIFEST'
+name: apply-test
+setup:
+  - echo applied >>/tmp/toolbx-apply
+  - true
+MANIFEST
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" apply --file "$BATS_TEST_TMPDIR/toolbox.yaml"
+
+  assert_success
+  refute_line "Created container: apply-test"
+  assert_line "Running in container apply-test: echo applied >>/tmp/toolbx-apply"
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" run --container apply-test cat /tmp/toolbx-apply
+
+  assert_success
+  assert_line --index 0 "applied"
+  assert_line --index 1 "applied"
+  assert [ ${#lines[@]} -eq 2 ]
+}
+
+@test "apply: Create a Toolbx container again after the manifest changed" {
+  pull_default_image
+
+  cat >"$BATS_TEST_TMPDIR/toolbox.toml" <<'MANIFEST'
+name = "apply-test"
+MANIFEST
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --file "$BATS_TEST_TMPDIR/toolbox.toml"
+
+  assert_success
+
+  cat >"$BATS_TEST_TMPDIR/toolbox.toml" <<'MANIFEST'
+name = "apply-test"
+env = ["TOOLBX_TEST_APPLY=bar"]
+MANIFEST
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" apply --file "$BATS_TEST_TMPDIR/toolbox.toml"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: container apply-test doesn't match $BATS_TEST_TMPDIR/toolbox.toml"
+  assert_line --index 1 "It needs to be created again to apply the changes."
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" --assumeyes apply --file "$BATS_TEST_TMPDIR/toolbox.toml"
+
+  assert_success
+  assert_line "Created container: apply-test"
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" run --container apply-test printenv TOOLBX_TEST_APPLY
+
+  assert_success
+  assert_line --index 0 "bar"
+  assert [ ${#lines[@]} -eq 1 ]
+}
+
+@test "apply: Keep the old Toolbx container if creating it again fails" {
+  pull_default_image
+
+  cat >"$BATS_TEST_TMPDIR/toolbox.toml" <<'MANIFEST'
+name = "apply-test"
+env = ["TOOLBX_TEST_APPLY=foo"]
+MANIFEST
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --file "$BATS_TEST_TMPDIR/toolbox.toml"
+
+  assert_success
+
+  cat >"$BATS_TEST_TMPDIR/toolbox.toml" <<'MANIFEST'
+name = "apply-test"
+image = "localhost/toolbx-apply-does-not-exist:1"
+MANIFEST
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" --assumeyes apply --file "$BATS_TEST_TMPDIR/toolbox.toml"
+
+  assert_failure
+
+  run podman container exists apply-test-rollback
+
+  assert_failure
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" run --container apply-test printenv TOOLBX_TEST_APPLY
+
+  assert_success
+  assert_line --index 0 "foo"
+  assert [ ${#lines[@]} -eq 1 ]
+}
+
+@test "apply: Try without a manifest" {
+  pushd "$BATS_TEST_TMPDIR" || return 1
+  run --keep-empty-lines --separate-stderr "$TOOLBX" apply
+  popd || return 1
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: none of toolbox.toml, toolbox.yaml, toolbox.yml found"
+  assert_line --index 1 "Use the '--file' option to select a manifest."
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
+@test "apply: Try a manifest with an unknown key" {
+  echo 'distribution = "fedora"' >"$BATS_TEST_TMPDIR/toolbox.toml"
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" apply --file "$BATS_TEST_TMPDIR/toolbox.toml"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: file $BATS_TEST_TMPDIR/toolbox.toml has unknown or invalid keys"
+  assert [ ${#stderr_lines[@]} -eq 1 ]
+}
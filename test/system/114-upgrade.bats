@@ -0,0 +1,152 @@
+# shellcheck shell=bats
+#
+# Copyright © 2026 Red Hat, Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+
+# bats file_tags=commands-options
+
+load 'libs/bats-support/load'
+load 'libs/bats-assert/load'
+load 'libs/helpers'
+
+setup() {
+  bats_require_minimum_version 1.10.0
+  cleanup_all
+}
+
+teardown() {
+  cleanup_all
+}
+
+
+@test "upgrade: Try a non-existent container" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" upgrade foo
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: container foo not found"
+  assert_line --index 1 "Use the 'create' command to create a Toolbx."
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
+@test "upgrade: Try using both --confirm and --rollback" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" upgrade --confirm --rollback
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: options --confirm and --rollback cannot be used together"
+  assert_line --index 1 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 2 ]
+}
+
+@test "upgrade: Try a non-existent authentication file" {
+  local file="$BATS_TEST_TMPDIR/non-existent-file"
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" upgrade --authfile "$file"
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: file $file not found"
+  assert_line --index 1 "'podman login' can be used to create the file."
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+}
+
+@test "upgrade: Try using both --confirm and --force" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" upgrade --confirm --force
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: option --force cannot be used with --confirm or --rollback"
+  assert_line --index 1 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 2 ]
+}
+
+@test "upgrade: Try to confirm a container that was not upgraded" {
+  create_container upgraded
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" upgrade --confirm upgraded
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: container upgraded was not upgraded"
+  assert [ ${#stderr_lines[@]} -eq 1 ]
+}
+
+@test "upgrade: Upgrade a container, keeping its options, and roll back" {
+  pull_default_image
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" create --env TOOLBX_TEST_UPGRADE=foo upgraded
+
+  assert_success
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" --assumeyes upgrade upgraded
+
+  assert_success
+  assert_line --index 0 "Upgraded container: upgraded"
+  assert_line --index 1 "Enter with: toolbox enter upgraded"
+  assert_line --index 2 "The old container was kept as upgraded-rollback."
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" run --container upgraded printenv TOOLBX_TEST_UPGRADE
+
+  assert_success
+  assert_line --index 0 "foo"
+  assert [ ${#lines[@]} -eq 1 ]
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" upgrade upgraded
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: container upgraded-rollback from an earlier upgrade already exists"
+  assert_line --index 1 "Use the '--confirm' or '--rollback' option first."
+  assert_line --index 2 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 3 ]
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" upgrade --rollback upgraded
+
+  assert_success
+  assert_line --index 0 "Restored container: upgraded"
+  assert [ ${#lines[@]} -eq 1 ]
+
+  run podman container exists upgraded-rollback
+
+  assert_failure
+}
+
+@test "upgrade: Upgrade a container and confirm" {
+  create_container upgraded
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" --assumeyes upgrade upgraded
+
+  assert_success
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" upgrade --confirm upgraded
+
+  assert_success
+  assert_line --index 0 "Removed container: upgraded-rollback"
+  assert [ ${#lines[@]} -eq 1 ]
+
+  run podman container exists upgraded
+
+  assert_success
+}
@@ -0,0 +1,92 @@
+# shellcheck shell=bats
+#
+# Copyright © 2026 Red Hat, Inc.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+
+# bats file_tags=commands-options
+
+load 'libs/bats-support/load'
+load 'libs/bats-assert/load'
+load 'libs/helpers'
+
+setup() {
+  bats_require_minimum_version 1.10.0
+  cleanup_all
+}
+
+teardown() {
+  cleanup_all
+}
+
+
+@test "prune: Smoke test" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" prune
+
+  assert_success
+  assert_line --index -1 --regexp '^Total reclaimed space: .+$'
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}
+
+@test "prune: Dangling image" {
+  local image
+  image="$(build_image_without_name)"
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" prune --dry-run
+
+  assert_success
+  assert_line --index 0 --regexp "^Would remove image $image \(.+\)$"
+  assert_line --index -1 --regexp '^Total space that would be reclaimed: .+$'
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+
+  local num_of_images
+  num_of_images="$(list_images)"
+  assert_equal "$num_of_images" 1
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" prune
+
+  assert_success
+  assert_line --index 0 --regexp "^Removed image $image \(.+\)$"
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+
+  num_of_images="$(list_images)"
+  assert_equal "$num_of_images" 0
+}
+
+@test "prune: Keep images used by containers (using --all)" {
+  pull_default_image
+  pull_distro_image fedora 34
+  create_default_container
+
+  run --keep-empty-lines --separate-stderr "$TOOLBX" prune --all
+
+  assert_success
+  assert_line --index 0 --regexp '^Removed image registry.fedoraproject.org/fedora-toolbox:34 \(.+\)$'
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+
+  local num_of_images
+  num_of_images="$(list_images)"
+  assert_equal "$num_of_images" 1
+}
+
+@test "prune: Try with an argument" {
+  run --keep-empty-lines --separate-stderr "$TOOLBX" prune foo
+
+  assert_failure
+  assert [ ${#lines[@]} -eq 0 ]
+  lines=("${stderr_lines[@]}")
+  assert_line --index 0 "Error: too many arguments for \"prune\""
+  assert_line --index 1 "Run 'toolbox --help' for usage."
+  assert [ ${#stderr_lines[@]} -eq 2 ]
+}
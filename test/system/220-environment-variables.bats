@@ -713,3 +713,34 @@ teardown_file() {
   # shellcheck disable=SC2154
   assert [ ${#stderr_lines[@]} -eq 0 ]
 }
+
+# bats test_tags=arch-fedora
+@test "environment variables: Preserve and discard variables using toolbox.conf" {
+  local config_file="$XDG_CONFIG_HOME/containers/toolbox.conf"
+
+  mkdir --parents "$XDG_CONFIG_HOME/containers"
+  cat <<EOF_CONFIG >"$config_file"
+[environment]
+preserve = ["TOOLBX_TEST_*", "https_proxy"]
+discard = ["XTERM_VERSION"]
+EOF_CONFIG
+
+  export TOOLBX_TEST_FOO="foo"
+  export https_proxy="http://proxy.example.com:3128"
+  export XTERM_VERSION="XTerm(385)"
+
+  # shellcheck disable=SC2016
+  run --keep-empty-lines --separate-stderr "$TOOLBX" run \
+    bash -c 'echo "$TOOLBX_TEST_FOO"; echo "$https_proxy"; echo "${XTERM_VERSION:-unset}"'
+
+  rm "$config_file"
+
+  assert_success
+  assert_line --index 0 "foo"
+  assert_line --index 1 "http://proxy.example.com:3128"
+  assert_line --index 2 "unset"
+  assert [ ${#lines[@]} -eq 3 ]
+
+  # shellcheck disable=SC2154
+  assert [ ${#stderr_lines[@]} -eq 0 ]
+}